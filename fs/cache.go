@@ -5,9 +5,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cyverse/go-irodsclient/irods/metrics"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
-	gocache "github.com/patrickmn/go-cache"
 )
 
 // MetadataCacheTimeoutSetting defines cache timeout for path
@@ -24,28 +24,40 @@ type FileSystemCache struct {
 	cacheTimeoutPaths                     []MetadataCacheTimeoutSetting
 	cacheTimeoutPathMap                   map[string]MetadataCacheTimeoutSetting
 	invalidateParentEntryCacheImmediately bool
-	entryCache                            *gocache.Cache
-	negativeEntryCache                    *gocache.Cache
-	dirCache                              *gocache.Cache
-	metadataCache                         *gocache.Cache
-	groupUsersCache                       *gocache.Cache
-	userGroupsCache                       *gocache.Cache
-	groupsCache                           *gocache.Cache
-	usersCache                            *gocache.Cache
-	aclCache                              *gocache.Cache
+	entryCache                            *lruCache
+	negativeEntryCache                    *lruCache
+	dirCache                              *lruCache
+	metadataCache                         *lruCache
+	groupUsersCache                       *lruCache
+	userGroupsCache                       *lruCache
+	groupsCache                           *lruCache
+	usersCache                            *lruCache
+	aclCache                              *lruCache
+	backend                               FileSystemCacheBackend
+	backendScope                          string
+	metrics                               *metrics.IRODSMetrics
 }
 
 // NewFileSystemCache creates a new FileSystemCache
 func NewFileSystemCache(cacheTimeout time.Duration, cleanup time.Duration, cacheTimeoutSettings []MetadataCacheTimeoutSetting, invalidateParentEntryCacheImmediately bool) *FileSystemCache {
-	entryCache := gocache.New(cacheTimeout, cleanup)
-	negativeEntryCache := gocache.New(cacheTimeout, cleanup)
-	dirCache := gocache.New(cacheTimeout, cleanup)
-	metadataCache := gocache.New(cacheTimeout, cleanup)
-	groupUsersCache := gocache.New(cacheTimeout, cleanup)
-	userGroupsCache := gocache.New(cacheTimeout, cleanup)
-	groupsCache := gocache.New(cacheTimeout, cleanup)
-	usersCache := gocache.New(cacheTimeout, cleanup)
-	aclCache := gocache.New(cacheTimeout, cleanup)
+	return NewFileSystemCacheWithBackend(cacheTimeout, cleanup, cacheTimeoutSettings, invalidateParentEntryCacheImmediately, nil)
+}
+
+// NewFileSystemCacheWithBackend creates a new FileSystemCache with a persistent backend
+// entry, dir, metadata and ACL caches are stored in the backend too, and loaded when they are not in memory
+// backend can be nil to keep caches only in memory
+func NewFileSystemCacheWithBackend(cacheTimeout time.Duration, cleanup time.Duration, cacheTimeoutSettings []MetadataCacheTimeoutSetting, invalidateParentEntryCacheImmediately bool, backend FileSystemCacheBackend) *FileSystemCache {
+	cacheMetrics := &metrics.IRODSMetrics{}
+
+	entryCache := newLRUCache(FileSystemCacheNameEntry, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	negativeEntryCache := newLRUCache(FileSystemCacheNameNegativeEntry, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	dirCache := newLRUCache(FileSystemCacheNameDir, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	metadataCache := newLRUCache(FileSystemCacheNameMetadata, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	groupUsersCache := newLRUCache(FileSystemCacheNameGroupUsers, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	userGroupsCache := newLRUCache(FileSystemCacheNameUserGroups, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	groupsCache := newLRUCache(FileSystemCacheNameGroups, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	usersCache := newLRUCache(FileSystemCacheNameUsers, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
+	aclCache := newLRUCache(FileSystemCacheNameACL, cacheTimeout, cleanup, estimateCacheItemSize, cacheMetrics)
 
 	if cacheTimeoutSettings == nil {
 		cacheTimeoutSettings = []MetadataCacheTimeoutSetting{}
@@ -72,9 +84,159 @@ func NewFileSystemCache(cacheTimeout time.Duration, cleanup time.Duration, cache
 		groupsCache:                           groupsCache,
 		usersCache:                            usersCache,
 		aclCache:                              aclCache,
+		backend:                               backend,
+		metrics:                               cacheMetrics,
 	}
 }
 
+// estimateCacheItemSize returns estimated memory size of a cache item in bytes
+func estimateCacheItemSize(key string, value interface{}) int64 {
+	// rough estimation of struct, pointer and slice headers
+	const overhead = 64
+
+	size := int64(overhead + len(key))
+
+	switch v := value.(type) {
+	case *Entry:
+		size += int64(overhead + len(v.Name) + len(v.Path) + len(v.Owner) + len(v.DataType) + len(v.CheckSum))
+	case []string:
+		for _, str := range v {
+			size += int64(16 + len(str))
+		}
+	case []*types.IRODSMeta:
+		for _, meta := range v {
+			size += int64(overhead + len(meta.Name) + len(meta.Value) + len(meta.Units))
+		}
+	case []*types.IRODSUser:
+		for _, user := range v {
+			size += int64(overhead + len(user.Name) + len(user.Zone))
+		}
+	case []*types.IRODSAccess:
+		for _, access := range v {
+			size += int64(overhead + len(access.Path) + len(access.UserName) + len(access.UserZone))
+		}
+	}
+
+	return size
+}
+
+// SetCacheLimit sets size limits of the named cache, such as FileSystemCacheNameEntry
+func (cache *FileSystemCache) SetCacheLimit(name string, limit FileSystemCacheLimit) {
+	switch name {
+	case FileSystemCacheNameEntry:
+		cache.entryCache.setLimit(limit)
+	case FileSystemCacheNameNegativeEntry:
+		cache.negativeEntryCache.setLimit(limit)
+	case FileSystemCacheNameDir:
+		cache.dirCache.setLimit(limit)
+	case FileSystemCacheNameMetadata:
+		cache.metadataCache.setLimit(limit)
+	case FileSystemCacheNameGroupUsers:
+		cache.groupUsersCache.setLimit(limit)
+	case FileSystemCacheNameUserGroups:
+		cache.userGroupsCache.setLimit(limit)
+	case FileSystemCacheNameGroups:
+		cache.groupsCache.setLimit(limit)
+	case FileSystemCacheNameUsers:
+		cache.usersCache.setLimit(limit)
+	case FileSystemCacheNameACL:
+		cache.aclCache.setLimit(limit)
+	}
+}
+
+// SetStaleGracePeriod sets how long entry and dir caches are served after expiration while they are refreshed
+// set 0 to disable serving stale caches
+func (cache *FileSystemCache) SetStaleGracePeriod(gracePeriod time.Duration) {
+	cache.entryCache.setStaleTTL(gracePeriod)
+	cache.dirCache.setStaleTTL(gracePeriod)
+}
+
+// SetBackendScope sets the scope of caches stored in the backend
+// caches of different scopes, e.g., accounts given by GetFileSystemCacheBackendScope, do not see each other in a shared backend
+func (cache *FileSystemCache) SetBackendScope(scope string) {
+	cache.backendScope = scope
+}
+
+// getBackendBucket returns the bucket in the backend, scoped
+func (cache *FileSystemCache) getBackendBucket(bucket string) string {
+	if len(cache.backendScope) == 0 {
+		return bucket
+	}
+	return cache.backendScope + "/" + bucket
+}
+
+// GetMetrics returns hit, miss and eviction counters of caches
+func (cache *FileSystemCache) GetMetrics() *metrics.IRODSMetrics {
+	return cache.metrics
+}
+
+// getExpireTime returns expire time of a cache added with the ttl, zero time means no expiration
+func (cache *FileSystemCache) getExpireTime(ttl time.Duration) time.Time {
+	if ttl == 0 {
+		ttl = cache.cacheTimeout
+	}
+
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(ttl)
+}
+
+// storeToBackend stores a cache to backend
+func (cache *FileSystemCache) storeToBackend(bucket string, key string, value interface{}, ttl time.Duration) {
+	if cache.backend == nil {
+		return
+	}
+
+	record, err := newFileSystemCacheRecord(key, value, cache.getExpireTime(ttl))
+	if err != nil {
+		return
+	}
+
+	// backend failures are ignored, caches in memory are still valid
+	cache.backend.Set(cache.getBackendBucket(bucket), key, record)
+}
+
+// loadFromBackend loads a cache from backend into value, returns remaining ttl
+func (cache *FileSystemCache) loadFromBackend(bucket string, key string, value interface{}) (time.Duration, bool) {
+	if cache.backend == nil {
+		return 0, false
+	}
+
+	record, ok := cache.backend.Get(cache.getBackendBucket(bucket), key)
+	if !ok {
+		return 0, false
+	}
+
+	ttl, ok := parseFileSystemCacheRecord(key, record, value)
+	if !ok {
+		// expired or written by other versions
+		cache.backend.Delete(cache.getBackendBucket(bucket), key)
+		return 0, false
+	}
+
+	return ttl, true
+}
+
+// deleteFromBackend deletes a cache from backend
+func (cache *FileSystemCache) deleteFromBackend(bucket string, key string) {
+	if cache.backend == nil {
+		return
+	}
+
+	cache.backend.Delete(cache.getBackendBucket(bucket), key)
+}
+
+// clearBackend deletes all caches in the bucket from backend
+func (cache *FileSystemCache) clearBackend(bucket string) {
+	if cache.backend == nil {
+		return
+	}
+
+	cache.backend.Clear(cache.getBackendBucket(bucket))
+}
+
 func (cache *FileSystemCache) getCacheTTLForPath(path string) time.Duration {
 	if len(cache.cacheTimeoutPathMap) == 0 {
 		// no data
@@ -109,11 +271,13 @@ func (cache *FileSystemCache) getCacheTTLForPath(path string) time.Duration {
 func (cache *FileSystemCache) AddEntryCache(entry *Entry) {
 	ttl := cache.getCacheTTLForPath(entry.Path)
 	cache.entryCache.Set(entry.Path, entry, ttl)
+	cache.storeToBackend(FileSystemCacheNameEntry, entry.Path, entry, ttl)
 }
 
 // RemoveEntryCache removes an entry cache
 func (cache *FileSystemCache) RemoveEntryCache(path string) {
 	cache.entryCache.Delete(path)
+	cache.deleteFromBackend(FileSystemCacheNameEntry, path)
 }
 
 // RemoveParentDirCache removes an entry cache for the parent path of the given path
@@ -121,6 +285,7 @@ func (cache *FileSystemCache) RemoveParentDirCache(path string) {
 	if cache.invalidateParentEntryCacheImmediately {
 		parentPath := util.GetIRODSPathDirname(path)
 		cache.entryCache.Delete(parentPath)
+		cache.deleteFromBackend(FileSystemCacheNameEntry, parentPath)
 	}
 }
 
@@ -131,12 +296,29 @@ func (cache *FileSystemCache) GetEntryCache(path string) *Entry {
 			return fsentry
 		}
 	}
+
+	fsentry := &Entry{}
+	if ttl, ok := cache.loadFromBackend(FileSystemCacheNameEntry, path, fsentry); ok {
+		cache.entryCache.Set(path, fsentry, ttl)
+		return fsentry
+	}
+	return nil
+}
+
+// GetStaleEntryCache returns an entry cache expired within stale grace period, returns nil if not exist or not expired
+func (cache *FileSystemCache) GetStaleEntryCache(path string) *Entry {
+	if entry, fresh, exist := cache.entryCache.GetStale(path); exist && !fresh {
+		if fsentry, ok := entry.(*Entry); ok {
+			return fsentry
+		}
+	}
 	return nil
 }
 
 // ClearEntryCache clears all entry caches
 func (cache *FileSystemCache) ClearEntryCache() {
 	cache.entryCache.Flush()
+	cache.clearBackend(FileSystemCacheNameEntry)
 }
 
 // AddNegativeEntryCache adds a negative entry cache
@@ -154,7 +336,7 @@ func (cache *FileSystemCache) RemoveNegativeEntryCache(path string) {
 func (cache *FileSystemCache) RemoveAllNegativeEntryCacheForPath(path string) {
 	prefix := fmt.Sprintf("%s/", path)
 	deleteKey := []string{}
-	for k := range cache.negativeEntryCache.Items() {
+	for _, k := range cache.negativeEntryCache.Keys() {
 		if k == path || strings.HasPrefix(k, prefix) {
 			deleteKey = append(deleteKey, k)
 		}
@@ -184,11 +366,13 @@ func (cache *FileSystemCache) ClearNegativeEntryCache() {
 func (cache *FileSystemCache) AddDirCache(path string, entries []string) {
 	ttl := cache.getCacheTTLForPath(path)
 	cache.dirCache.Set(path, entries, ttl)
+	cache.storeToBackend(FileSystemCacheNameDir, path, entries, ttl)
 }
 
 // RemoveDirCache removes a dir cache
 func (cache *FileSystemCache) RemoveDirCache(path string) {
 	cache.dirCache.Delete(path)
+	cache.deleteFromBackend(FileSystemCacheNameDir, path)
 }
 
 // GetDirCache retrives a dir cache
@@ -199,23 +383,42 @@ func (cache *FileSystemCache) GetDirCache(path string) []string {
 			return entries
 		}
 	}
+
+	entries := []string{}
+	if ttl, ok := cache.loadFromBackend(FileSystemCacheNameDir, path, &entries); ok {
+		cache.dirCache.Set(path, entries, ttl)
+		return entries
+	}
+	return nil
+}
+
+// GetStaleDirCache returns a dir cache expired within stale grace period, returns nil if not exist or not expired
+func (cache *FileSystemCache) GetStaleDirCache(path string) []string {
+	if data, fresh, exist := cache.dirCache.GetStale(path); exist && !fresh {
+		if entries, ok := data.([]string); ok {
+			return entries
+		}
+	}
 	return nil
 }
 
 // ClearDirCache clears all dir caches
 func (cache *FileSystemCache) ClearDirCache() {
 	cache.dirCache.Flush()
+	cache.clearBackend(FileSystemCacheNameDir)
 }
 
 // AddMetadataCache adds a metadata cache
 func (cache *FileSystemCache) AddMetadataCache(path string, metas []*types.IRODSMeta) {
 	ttl := cache.getCacheTTLForPath(path)
 	cache.metadataCache.Set(path, metas, ttl)
+	cache.storeToBackend(FileSystemCacheNameMetadata, path, metas, ttl)
 }
 
 // RemoveMetadataCache removes a metadata cache
 func (cache *FileSystemCache) RemoveMetadataCache(path string) {
 	cache.metadataCache.Delete(path)
+	cache.deleteFromBackend(FileSystemCacheNameMetadata, path)
 }
 
 // GetMetadataCache retrieves a metadata cache
@@ -226,12 +429,19 @@ func (cache *FileSystemCache) GetMetadataCache(path string) []*types.IRODSMeta {
 			return metas
 		}
 	}
+
+	metas := []*types.IRODSMeta{}
+	if ttl, ok := cache.loadFromBackend(FileSystemCacheNameMetadata, path, &metas); ok {
+		cache.metadataCache.Set(path, metas, ttl)
+		return metas
+	}
 	return nil
 }
 
 // ClearMetadataCache clears all metadata caches
 func (cache *FileSystemCache) ClearMetadataCache() {
 	cache.metadataCache.Flush()
+	cache.clearBackend(FileSystemCacheNameMetadata)
 }
 
 // AddGroupUsersCache adds a group user (users in a group) cache
@@ -322,6 +532,7 @@ func (cache *FileSystemCache) GetUsersCache() []*types.IRODSUser {
 func (cache *FileSystemCache) AddACLsCache(path string, accesses []*types.IRODSAccess) {
 	ttl := cache.getCacheTTLForPath(path)
 	cache.aclCache.Set(path, accesses, ttl)
+	cache.storeToBackend(FileSystemCacheNameACL, path, accesses, ttl)
 }
 
 // AddACLsCacheMulti adds multiple ACLs caches
@@ -342,12 +553,14 @@ func (cache *FileSystemCache) AddACLsCacheMulti(accesses []*types.IRODSAccess) {
 	for path, access := range m {
 		ttl := cache.getCacheTTLForPath(path)
 		cache.aclCache.Set(path, access, ttl)
+		cache.storeToBackend(FileSystemCacheNameACL, path, access, ttl)
 	}
 }
 
 // RemoveACLsCache removes a ACLs cache
 func (cache *FileSystemCache) RemoveACLsCache(path string) {
 	cache.aclCache.Delete(path)
+	cache.deleteFromBackend(FileSystemCacheNameACL, path)
 }
 
 // GetACLsCache retrives a ACLs cache
@@ -358,10 +571,17 @@ func (cache *FileSystemCache) GetACLsCache(path string) []*types.IRODSAccess {
 			return entries
 		}
 	}
+
+	accesses := []*types.IRODSAccess{}
+	if ttl, ok := cache.loadFromBackend(FileSystemCacheNameACL, path, &accesses); ok {
+		cache.aclCache.Set(path, accesses, ttl)
+		return accesses
+	}
 	return nil
 }
 
 // ClearACLsCache clears all ACLs caches
 func (cache *FileSystemCache) ClearACLsCache() {
 	cache.aclCache.Flush()
+	cache.clearBackend(FileSystemCacheNameACL)
 }
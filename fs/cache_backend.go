@@ -0,0 +1,183 @@
+package fs
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/rs/xid"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// FileSystemCacheFormatVersion is a version of the format of cache items stored in backend
+	// increase it when cached data structures change, so caches written by older library versions are invalidated
+	FileSystemCacheFormatVersion = 1
+)
+
+// FileSystemCacheBackend is a persistent storage of filesystem caches
+// caches are grouped in buckets named after caches, such as FileSystemCacheNameEntry
+// values are opaque to backends, expiration and versioning are handled by FileSystemCache
+type FileSystemCacheBackend interface {
+	// Get returns the value stored, returns false if not exist
+	Get(bucket string, key string) ([]byte, bool)
+	// Set stores the value
+	Set(bucket string, key string, value []byte) error
+	// Delete deletes the value
+	Delete(bucket string, key string) error
+	// Clear deletes all values in the bucket
+	Clear(bucket string) error
+}
+
+// GetFileSystemCacheBackendScope returns a scope of caches stored in backend for the account
+// caches differ by servers, zones and client users as permissions apply
+func GetFileSystemCacheBackendScope(account *types.IRODSAccount) string {
+	identity := fmt.Sprintf("%s:%d/%s/%s", account.Host, account.Port, account.ClientZone, account.ClientUser)
+	hash := sha1.Sum([]byte(identity))
+	return hex.EncodeToString(hash[:])
+}
+
+// fileSystemCacheRecord is a cache item stored in backend
+type fileSystemCacheRecord struct {
+	Version    int             `json:"version"`
+	Key        string          `json:"key"`
+	ExpireTime time.Time       `json:"expire_time"` // zero means no expiration
+	Value      json.RawMessage `json:"value"`
+}
+
+// newFileSystemCacheRecord creates a record of the value
+func newFileSystemCacheRecord(key string, value interface{}, expireTime time.Time) ([]byte, error) {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal cache value for %s: %w", key, err)
+	}
+
+	record := fileSystemCacheRecord{
+		Version:    FileSystemCacheFormatVersion,
+		Key:        key,
+		ExpireTime: expireTime,
+		Value:      valueBytes,
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal cache record for %s: %w", key, err)
+	}
+
+	return recordBytes, nil
+}
+
+// parseFileSystemCacheRecord parses the record into value, returns remaining ttl
+// returns false if the record is invalid, expired or written by other versions
+func parseFileSystemCacheRecord(key string, recordBytes []byte, value interface{}) (time.Duration, bool) {
+	record := fileSystemCacheRecord{}
+	err := json.Unmarshal(recordBytes, &record)
+	if err != nil {
+		return 0, false
+	}
+
+	if record.Version != FileSystemCacheFormatVersion || record.Key != key {
+		return 0, false
+	}
+
+	ttl := time.Duration(-1) // no expiration
+	if !record.ExpireTime.IsZero() {
+		ttl = time.Until(record.ExpireTime)
+		if ttl <= 0 {
+			// expired
+			return 0, false
+		}
+	}
+
+	err = json.Unmarshal(record.Value, value)
+	if err != nil {
+		return 0, false
+	}
+
+	return ttl, true
+}
+
+// FileSystemCacheDiskBackend is a FileSystemCacheBackend storing caches in local files
+type FileSystemCacheDiskBackend struct {
+	rootPath string
+}
+
+// NewFileSystemCacheDiskBackend creates a new FileSystemCacheDiskBackend
+func NewFileSystemCacheDiskBackend(rootPath string) (*FileSystemCacheDiskBackend, error) {
+	err := os.MkdirAll(rootPath, 0700)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to make cache dir %s: %w", rootPath, err)
+	}
+
+	return &FileSystemCacheDiskBackend{
+		rootPath: rootPath,
+	}, nil
+}
+
+// getFilePath returns a path to the file storing the key
+func (backend *FileSystemCacheDiskBackend) getFilePath(bucket string, key string) string {
+	hash := sha1.Sum([]byte(key))
+	hashString := hex.EncodeToString(hash[:])
+	return filepath.Join(backend.rootPath, bucket, hashString[:2], hashString)
+}
+
+// Get returns the value stored, returns false if not exist
+func (backend *FileSystemCacheDiskBackend) Get(bucket string, key string) ([]byte, bool) {
+	value, err := os.ReadFile(backend.getFilePath(bucket, key))
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Set stores the value
+func (backend *FileSystemCacheDiskBackend) Set(bucket string, key string, value []byte) error {
+	filePath := backend.getFilePath(bucket, key)
+
+	err := os.MkdirAll(filepath.Dir(filePath), 0700)
+	if err != nil {
+		return xerrors.Errorf("failed to make cache dir for %s: %w", key, err)
+	}
+
+	// write to a temp file and rename, so readers do not see partial values
+	tempPath := filePath + "." + xid.New().String()
+	err = os.WriteFile(tempPath, value, 0600)
+	if err != nil {
+		os.Remove(tempPath)
+		return xerrors.Errorf("failed to write cache for %s: %w", key, err)
+	}
+
+	err = os.Rename(tempPath, filePath)
+	if err != nil {
+		os.Remove(tempPath)
+		return xerrors.Errorf("failed to rename cache for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete deletes the value
+func (backend *FileSystemCacheDiskBackend) Delete(bucket string, key string) error {
+	err := os.Remove(backend.getFilePath(bucket, key))
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("failed to delete cache for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Clear deletes all values in the bucket
+func (backend *FileSystemCacheDiskBackend) Clear(bucket string) error {
+	err := os.RemoveAll(filepath.Join(backend.rootPath, bucket))
+	if err != nil {
+		return xerrors.Errorf("failed to clear cache bucket %s: %w", bucket, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package fs
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/metrics"
+)
+
+const (
+	// FileSystemCacheNameEntry is a name of entry cache
+	FileSystemCacheNameEntry = "entry"
+	// FileSystemCacheNameNegativeEntry is a name of negative entry cache
+	FileSystemCacheNameNegativeEntry = "negative_entry"
+	// FileSystemCacheNameDir is a name of dir cache
+	FileSystemCacheNameDir = "dir"
+	// FileSystemCacheNameMetadata is a name of metadata cache
+	FileSystemCacheNameMetadata = "metadata"
+	// FileSystemCacheNameGroupUsers is a name of group users cache
+	FileSystemCacheNameGroupUsers = "group_users"
+	// FileSystemCacheNameUserGroups is a name of user groups cache
+	FileSystemCacheNameUserGroups = "user_groups"
+	// FileSystemCacheNameGroups is a name of groups cache
+	FileSystemCacheNameGroups = "groups"
+	// FileSystemCacheNameUsers is a name of users cache
+	FileSystemCacheNameUsers = "users"
+	// FileSystemCacheNameACL is a name of ACL cache
+	FileSystemCacheNameACL = "acl"
+)
+
+// FileSystemCacheLimit defines size limits of a cache, least recently used items are evicted when a limit is exceeded
+// 0 means unlimited
+type FileSystemCacheLimit struct {
+	MaxEntries int
+	// MaxBytes is a limit of estimated memory size of items
+	MaxBytes int64
+}
+
+// lruCacheItem is an item in lruCache
+type lruCacheItem struct {
+	key        string
+	value      interface{}
+	size       int64
+	expireTime time.Time // zero means no expiration
+}
+
+// isExpired returns true if the item is expired
+func (item *lruCacheItem) isExpired(now time.Time) bool {
+	return !item.expireTime.IsZero() && now.After(item.expireTime)
+}
+
+// isRemovable returns true if the item is expired and its stale period is over
+func (item *lruCacheItem) isRemovable(now time.Time, staleTTL time.Duration) bool {
+	return !item.expireTime.IsZero() && now.After(item.expireTime.Add(staleTTL))
+}
+
+// lruCache is a cache with expiration and LRU eviction
+// ttl of 0 uses default ttl and negative ttl means no expiration, as go-cache does
+// expired items are kept for staleTTL, and can be read with GetStale
+type lruCache struct {
+	name            string
+	defaultTTL      time.Duration
+	staleTTL        time.Duration
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+	limit           FileSystemCacheLimit
+	sizeEstimator   func(key string, value interface{}) int64
+	items           map[string]*list.Element
+	lru             *list.List // front is the most recently used
+	bytes           int64
+	metrics         *metrics.IRODSMetrics
+	mutex           sync.Mutex
+}
+
+// newLRUCache creates a new lruCache
+func newLRUCache(name string, defaultTTL time.Duration, cleanupInterval time.Duration, sizeEstimator func(key string, value interface{}) int64, metrics *metrics.IRODSMetrics) *lruCache {
+	return &lruCache{
+		name:            name,
+		defaultTTL:      defaultTTL,
+		staleTTL:        0,
+		cleanupInterval: cleanupInterval,
+		lastCleanup:     time.Now(),
+		limit:           FileSystemCacheLimit{},
+		sizeEstimator:   sizeEstimator,
+		items:           map[string]*list.Element{},
+		lru:             list.New(),
+		bytes:           0,
+		metrics:         metrics,
+	}
+}
+
+// setLimit sets size limits
+func (cache *lruCache) setLimit(limit FileSystemCacheLimit) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.limit = limit
+	cache.evict()
+}
+
+// setStaleTTL sets how long expired items are kept
+func (cache *lruCache) setStaleTTL(staleTTL time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.staleTTL = staleTTL
+}
+
+// Set adds an item
+func (cache *lruCache) Set(key string, value interface{}, ttl time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := time.Now()
+
+	if ttl == 0 {
+		ttl = cache.defaultTTL
+	}
+
+	expireTime := time.Time{}
+	if ttl > 0 {
+		expireTime = now.Add(ttl)
+	}
+
+	item := &lruCacheItem{
+		key:        key,
+		value:      value,
+		size:       cache.sizeEstimator(key, value),
+		expireTime: expireTime,
+	}
+
+	if element, ok := cache.items[key]; ok {
+		cache.removeElement(element)
+	}
+
+	cache.items[key] = cache.lru.PushFront(item)
+	cache.bytes += item.size
+
+	if cache.cleanupInterval > 0 && now.Sub(cache.lastCleanup) >= cache.cleanupInterval {
+		cache.cleanup(now)
+	}
+
+	cache.evict()
+}
+
+// Get returns an item
+func (cache *lruCache) Get(key string) (interface{}, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.items[key]
+	if !ok {
+		cache.metrics.IncreaseCounterForNamedCacheMiss(cache.name, 1)
+		return nil, false
+	}
+
+	now := time.Now()
+	item := element.Value.(*lruCacheItem)
+	if item.isExpired(now) {
+		if item.isRemovable(now, cache.staleTTL) {
+			cache.removeElement(element)
+		}
+		cache.metrics.IncreaseCounterForNamedCacheMiss(cache.name, 1)
+		return nil, false
+	}
+
+	cache.lru.MoveToFront(element)
+	cache.metrics.IncreaseCounterForNamedCacheHit(cache.name, 1)
+	return item.value, true
+}
+
+// GetStale returns an item even if it is expired, as long as its stale period is not over
+// returns false as the second return value if the item is expired
+func (cache *lruCache) GetStale(key string) (interface{}, bool, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.items[key]
+	if !ok {
+		return nil, false, false
+	}
+
+	now := time.Now()
+	item := element.Value.(*lruCacheItem)
+	if item.isRemovable(now, cache.staleTTL) {
+		cache.removeElement(element)
+		return nil, false, false
+	}
+
+	cache.lru.MoveToFront(element)
+	return item.value, !item.isExpired(now), true
+}
+
+// Delete deletes an item
+func (cache *lruCache) Delete(key string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.items[key]; ok {
+		cache.removeElement(element)
+	}
+}
+
+// Flush deletes all items
+func (cache *lruCache) Flush() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.items = map[string]*list.Element{}
+	cache.lru.Init()
+	cache.bytes = 0
+}
+
+// Keys returns keys of all items not expired
+func (cache *lruCache) Keys() []string {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := time.Now()
+	keys := []string{}
+	for key, element := range cache.items {
+		if !element.Value.(*lruCacheItem).isExpired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Len returns the number of items, including expired items not removed yet
+func (cache *lruCache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return len(cache.items)
+}
+
+// cleanup removes expired items whose stale period is over
+func (cache *lruCache) cleanup(now time.Time) {
+	for _, element := range cache.items {
+		if element.Value.(*lruCacheItem).isRemovable(now, cache.staleTTL) {
+			cache.removeElement(element)
+		}
+	}
+
+	cache.lastCleanup = now
+}
+
+// evict removes least recently used items until the cache fits in its limits
+func (cache *lruCache) evict() {
+	evicted := uint64(0)
+	for cache.isOverLimit() {
+		element := cache.lru.Back()
+		if element == nil {
+			break
+		}
+
+		cache.removeElement(element)
+		evicted++
+	}
+
+	if evicted > 0 {
+		cache.metrics.IncreaseCounterForNamedCacheEviction(cache.name, evicted)
+	}
+}
+
+// isOverLimit returns true if the cache exceeds its limits
+func (cache *lruCache) isOverLimit() bool {
+	if cache.limit.MaxEntries > 0 && len(cache.items) > cache.limit.MaxEntries {
+		return true
+	}
+
+	if cache.limit.MaxBytes > 0 && cache.bytes > cache.limit.MaxBytes {
+		return true
+	}
+
+	return false
+}
+
+// removeElement removes the item
+func (cache *lruCache) removeElement(element *list.Element) {
+	item := element.Value.(*lruCacheItem)
+
+	cache.lru.Remove(element)
+	delete(cache.items, item.key)
+	cache.bytes -= item.size
+}
@@ -0,0 +1,52 @@
+package fs
+
+import (
+	"sync"
+)
+
+// fileSystemCacheRefresher refreshes caches in background
+// concurrent refreshes of the same key are deduplicated, only the first one runs
+type fileSystemCacheRefresher struct {
+	inflight  map[string]bool
+	waitGroup sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+// newFileSystemCacheRefresher creates a new fileSystemCacheRefresher
+func newFileSystemCacheRefresher() *fileSystemCacheRefresher {
+	return &fileSystemCacheRefresher{
+		inflight:  map[string]bool{},
+		waitGroup: sync.WaitGroup{},
+		mutex:     sync.Mutex{},
+	}
+}
+
+// refresh runs refreshFunc in background, returns false if a refresh of the key is already running
+func (refresher *fileSystemCacheRefresher) refresh(key string, refreshFunc func()) bool {
+	refresher.mutex.Lock()
+	defer refresher.mutex.Unlock()
+
+	if refresher.inflight[key] {
+		return false
+	}
+
+	refresher.inflight[key] = true
+	refresher.waitGroup.Add(1)
+
+	go func() {
+		defer refresher.waitGroup.Done()
+
+		refreshFunc()
+
+		refresher.mutex.Lock()
+		delete(refresher.inflight, key)
+		refresher.mutex.Unlock()
+	}()
+
+	return true
+}
+
+// wait waits until all refreshes running are done
+func (refresher *fileSystemCacheRefresher) wait() {
+	refresher.waitGroup.Wait()
+}
@@ -1,6 +1,11 @@
 package fs
 
-import "time"
+import (
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/session"
+)
 
 const (
 	// FileSystemConnectionErrorTimeoutDefault is a default timeout value of connection error
@@ -19,6 +24,14 @@ const (
 	FileSystemTimeoutDefault = 5 * time.Minute
 	// FileSystemTCPBufferSizeDefault is a default value of tcp buffer size
 	FileSystemTCPBufferSizeDefault = 4 * 1024 * 1024
+	// FileSystemReadAheadBlockSizeDefault is a default size of a block prefetched by read-ahead
+	FileSystemReadAheadBlockSizeDefault = 256 * 1024
+	// FileSystemReadAheadWindowDefault is a default number of blocks prefetched by read-ahead, 0 disables read-ahead
+	FileSystemReadAheadWindowDefault = 0
+	// FileSystemWriteBufferSizeDefault is a default size of write buffer of a file handle, 0 disables write buffering
+	FileSystemWriteBufferSizeDefault = 0
+	// FileSystemConnectionReservedHighPriorityDefault is a default number of connections reserved for metadata operations
+	FileSystemConnectionReservedHighPriorityDefault = 1
 )
 
 // FileSystemConfig is a struct for file system configuration
@@ -41,6 +54,56 @@ type FileSystemConfig struct {
 	// at subdir/file creation/deletion
 	// turn to false to allow short cache inconsistency
 	InvalidateParentEntryCacheImmediately bool
+	// retry policy for transient failures
+	// idempotent operations (stat, list, query, download) are retried on a fresh connection
+	// mutating operations are retried only when RetryPolicy.RetryMutatingOperations is set
+	// reads and writes of opened FileHandles are not retried, as the server-side file descriptor is bound to its connection
+	RetryPolicy *session.RetryPolicy
+	// read-ahead for sequential reads of file handles
+	// ReadAheadWindow blocks of ReadAheadBlockSize are requested in a pipeline
+	// set ReadAheadWindow to 0 to disable read-ahead
+	ReadAheadBlockSize int
+	ReadAheadWindow    int
+	// WriteBufferSize is the size of write buffer of a file handle
+	// small and adjacent writes are coalesced into blocks of WriteBufferSize and flushed asynchronously
+	// set 0 to disable write buffering
+	WriteBufferSize int
+	// DataCacheRootPath is a local dir to cache contents of data objects read, empty string disables data cache
+	// DataCacheBlockSize and DataCacheSizeMax set the size of a block cached and the size limit of the cache
+	DataCacheRootPath  string
+	DataCacheBlockSize int
+	DataCacheSizeMax   int64
+	// CacheBackend is a persistent storage of entry, dir, metadata and ACL caches, nil keeps caches only in memory
+	// e.g., NewFileSystemCacheDiskBackend stores caches in local files, so they survive process restarts
+	CacheBackend FileSystemCacheBackend
+	// CacheLimits bounds the size of caches in memory, keyed by cache name, such as FileSystemCacheNameEntry
+	// least recently used items are evicted when a limit is exceeded, caches not listed are unlimited
+	CacheLimits map[string]FileSystemCacheLimit
+	// CacheStaleGracePeriod is how long entry and dir caches are served after expiration, while they are refreshed in background
+	// set 0 to disable serving stale caches, so expired caches are retrieved from the server before returning
+	CacheStaleGracePeriod time.Duration
+	// ConnectionWaitTimeout is how long to wait for a connection when all connections are occupied
+	// an in-use connection is shared after the timeout, set 0 to share without waiting
+	ConnectionWaitTimeout time.Duration
+	// ConnectionReservedHighPriority is the number of connections that data transfers can't occupy,
+	// so metadata operations are not starved by transfers occupying all connections
+	ConnectionReservedHighPriority int
+	// ConnectionHealthCheckInterval is how often idle connections are pinged, dead ones are replaced with new connections
+	// set 0 to disable background health checks
+	ConnectionHealthCheckInterval time.Duration
+	// ConnectionValidateOnBorrow pings an idle connection before reuse, a dead one is replaced with a new connection
+	ConnectionValidateOnBorrow bool
+	// TCPKeepAlive is a period of TCP keepalive probes, 0 uses the OS default, negative disables TCP keepalive
+	TCPKeepAlive time.Duration
+	// Dialer dials the server, e.g., via a proxy created with connection.NewDialerFromEnvironment, dials directly if nil
+	Dialer connection.Dialer
+	// EndpointSelectionPolicy is a policy to choose one of catalog providers, the account and its AdditionalEndpoints, for new connections
+	EndpointSelectionPolicy session.EndpointSelectionPolicy
+	// EndpointBackoff is how long a catalog provider failed to connect is not chosen, new connections fail over to others
+	EndpointBackoff time.Duration
+	// PAMTokenRefreshHandler is called when a new pam token is issued using password, e.g., when the old one is expired
+	// use ICommandsEnvironmentManager.SavePAMToken to persist the token to .irodsA
+	PAMTokenRefreshHandler session.PAMTokenRefreshHandler
 }
 
 // NewFileSystemConfig create a FileSystemConfig
@@ -64,6 +127,23 @@ func NewFileSystemConfig(applicationName string, connectionErrorTimeout time.Dur
 		CacheTimeoutSettings:                  cacheTimeoutSettings,
 		StartNewTransaction:                   startNewTransaction,
 		InvalidateParentEntryCacheImmediately: invalidateParentEntryCacheImmediately,
+		RetryPolicy:                           session.NewRetryPolicyWithDefault(),
+		ReadAheadBlockSize:                    FileSystemReadAheadBlockSizeDefault,
+		ReadAheadWindow:                       FileSystemReadAheadWindowDefault,
+		WriteBufferSize:                       FileSystemWriteBufferSizeDefault,
+		DataCacheRootPath:                     "",
+		DataCacheBlockSize:                    DataCacheBlockSizeDefault,
+		DataCacheSizeMax:                      DataCacheSizeMaxDefault,
+		CacheBackend:                          nil,
+		CacheLimits:                           map[string]FileSystemCacheLimit{},
+		CacheStaleGracePeriod:                 0,
+		ConnectionWaitTimeout:                 session.IRODSSessionConnectionWaitTimeoutDefault,
+		ConnectionReservedHighPriority:        FileSystemConnectionReservedHighPriorityDefault,
+		ConnectionHealthCheckInterval:         session.IRODSSessionConnectionHealthCheckIntervalDefault,
+		ConnectionValidateOnBorrow:            false,
+		TCPKeepAlive:                          session.IRODSSessionTCPKeepAliveDefault,
+		EndpointSelectionPolicy:               session.IRODSSessionEndpointSelectionPolicyDefault,
+		EndpointBackoff:                       session.IRODSSessionEndpointBackoffDefault,
 	}
 }
 
@@ -83,5 +163,50 @@ func NewFileSystemConfigWithDefault(applicationName string) *FileSystemConfig {
 		CacheCleanupTime:                      FileSystemTimeoutDefault,
 		StartNewTransaction:                   true,
 		InvalidateParentEntryCacheImmediately: true,
+		RetryPolicy:                           session.NewRetryPolicyWithDefault(),
+		ReadAheadBlockSize:                    FileSystemReadAheadBlockSizeDefault,
+		ReadAheadWindow:                       FileSystemReadAheadWindowDefault,
+		WriteBufferSize:                       FileSystemWriteBufferSizeDefault,
+		DataCacheRootPath:                     "",
+		DataCacheBlockSize:                    DataCacheBlockSizeDefault,
+		DataCacheSizeMax:                      DataCacheSizeMaxDefault,
+		CacheBackend:                          nil,
+		CacheLimits:                           map[string]FileSystemCacheLimit{},
+		CacheStaleGracePeriod:                 0,
+		ConnectionWaitTimeout:                 session.IRODSSessionConnectionWaitTimeoutDefault,
+		ConnectionReservedHighPriority:        FileSystemConnectionReservedHighPriorityDefault,
+		ConnectionHealthCheckInterval:         session.IRODSSessionConnectionHealthCheckIntervalDefault,
+		ConnectionValidateOnBorrow:            false,
+		TCPKeepAlive:                          session.IRODSSessionTCPKeepAliveDefault,
+		EndpointSelectionPolicy:               session.IRODSSessionEndpointSelectionPolicyDefault,
+		EndpointBackoff:                       session.IRODSSessionEndpointBackoffDefault,
 	}
 }
+
+// applyToSessionConfig copies settings shared with session configuration to sessConfig
+func (config *FileSystemConfig) applyToSessionConfig(sessConfig *session.IRODSSessionConfig) {
+	sessConfig.RetryPolicy = config.RetryPolicy
+	sessConfig.ConnectionWaitTimeout = config.ConnectionWaitTimeout
+	sessConfig.ConnectionReservedHighPriority = config.ConnectionReservedHighPriority
+	sessConfig.ConnectionHealthCheckInterval = config.ConnectionHealthCheckInterval
+	sessConfig.ConnectionValidateOnBorrow = config.ConnectionValidateOnBorrow
+	sessConfig.TCPKeepAlive = config.TCPKeepAlive
+	sessConfig.PAMTokenRefreshHandler = config.PAMTokenRefreshHandler
+	sessConfig.EndpointSelectionPolicy = config.EndpointSelectionPolicy
+	sessConfig.EndpointBackoff = config.EndpointBackoff
+	sessConfig.Dialer = config.Dialer
+}
+
+// applySessionConfig copies settings shared with session configuration from sessConfig
+func (config *FileSystemConfig) applySessionConfig(sessConfig *session.IRODSSessionConfig) {
+	config.RetryPolicy = sessConfig.RetryPolicy
+	config.ConnectionWaitTimeout = sessConfig.ConnectionWaitTimeout
+	config.ConnectionReservedHighPriority = sessConfig.ConnectionReservedHighPriority
+	config.ConnectionHealthCheckInterval = sessConfig.ConnectionHealthCheckInterval
+	config.ConnectionValidateOnBorrow = sessConfig.ConnectionValidateOnBorrow
+	config.TCPKeepAlive = sessConfig.TCPKeepAlive
+	config.PAMTokenRefreshHandler = sessConfig.PAMTokenRefreshHandler
+	config.EndpointSelectionPolicy = sessConfig.EndpointSelectionPolicy
+	config.EndpointBackoff = sessConfig.EndpointBackoff
+	config.Dialer = sessConfig.Dialer
+}
@@ -0,0 +1,341 @@
+package fs
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/rs/xid"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DataCacheBlockSizeDefault is a default size of a block cached
+	DataCacheBlockSizeDefault = 1024 * 1024
+	// DataCacheSizeMaxDefault is a default max size of the cache on disk
+	DataCacheSizeMaxDefault = 1024 * 1024 * 1024
+)
+
+// dataCacheBlock is a block stored in the cache
+type dataCacheBlock struct {
+	path string // relative path to the block file
+	size int64
+}
+
+// DataCache is a local disk cache of data object contents
+// contents are stored in fixed-size blocks, keyed by data object ID, replica, modification time, size and checksum
+// least recently used blocks are evicted when the cache exceeds its size limit
+type DataCache struct {
+	rootPath  string
+	blockSize int
+	sizeMax   int64
+	size      int64
+	lru       *list.List               // front is the most recently used
+	blocks    map[string]*list.Element // relative path => element of lru
+	mutex     sync.Mutex
+}
+
+// NewDataCache creates a new DataCache, blocks already stored under rootPath are reused
+func NewDataCache(rootPath string, blockSize int, sizeMax int64) (*DataCache, error) {
+	if blockSize <= 0 {
+		return nil, xerrors.Errorf("invalid block size %d", blockSize)
+	}
+
+	err := os.MkdirAll(rootPath, 0700)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to make data cache dir %s: %w", rootPath, err)
+	}
+
+	cache := &DataCache{
+		rootPath:  rootPath,
+		blockSize: blockSize,
+		sizeMax:   sizeMax,
+		size:      0,
+		lru:       list.New(),
+		blocks:    map[string]*list.Element{},
+		mutex:     sync.Mutex{},
+	}
+
+	err = cache.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// load loads blocks stored on disk, in order of access time
+func (cache *DataCache) load() error {
+	type storedBlock struct {
+		block   *dataCacheBlock
+		modTime int64
+	}
+
+	storedBlocks := []storedBlock{}
+
+	// root/<data object id>_<replica number>/<version>/<block id>
+	blockPaths, err := filepath.Glob(filepath.Join(cache.rootPath, "*", "*", "*"))
+	if err != nil {
+		return xerrors.Errorf("failed to list data cache dir %s: %w", cache.rootPath, err)
+	}
+
+	for _, blockPath := range blockPaths {
+		if strings.Contains(filepath.Base(blockPath), ".") {
+			// temp file not completed
+			os.Remove(blockPath)
+			continue
+		}
+
+		stat, err := os.Stat(blockPath)
+		if err != nil || !stat.Mode().IsRegular() {
+			continue
+		}
+
+		relPath, err := filepath.Rel(cache.rootPath, blockPath)
+		if err != nil {
+			continue
+		}
+
+		storedBlocks = append(storedBlocks, storedBlock{
+			block: &dataCacheBlock{
+				path: relPath,
+				size: stat.Size(),
+			},
+			modTime: stat.ModTime().UnixNano(),
+		})
+	}
+
+	sort.Slice(storedBlocks, func(i int, j int) bool {
+		return storedBlocks[i].modTime > storedBlocks[j].modTime
+	})
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for _, stored := range storedBlocks {
+		cache.blocks[stored.block.path] = cache.lru.PushBack(stored.block)
+		cache.size += stored.block.size
+	}
+
+	cache.evict()
+	return nil
+}
+
+// GetBlockSize returns block size
+func (cache *DataCache) GetBlockSize() int {
+	return cache.blockSize
+}
+
+// GetSize returns total size of blocks cached
+func (cache *DataCache) GetSize() int64 {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.size
+}
+
+// getDataObjectDir returns a relative path to the dir of the replica
+func (cache *DataCache) getDataObjectDir(entry *Entry, replica *types.IRODSFileDescriptorInfo) string {
+	if replica == nil {
+		return strconv.FormatInt(entry.ID, 10)
+	}
+	return fmt.Sprintf("%d_%d", entry.ID, replica.ReplicaNumber)
+}
+
+// getVersionKey returns a key for the version of data object content
+// modification time has second precision and checksum may be empty, so size and replica info are added
+func (cache *DataCache) getVersionKey(entry *Entry, replica *types.IRODSFileDescriptorInfo) string {
+	version := fmt.Sprintf("%d:%d:%s:%x", entry.ModifyTime.UnixNano(), entry.Size, entry.CheckSumAlgorithm, entry.CheckSum)
+	if replica != nil {
+		replicaChecksum := ""
+		if replica.Checksum != nil {
+			replicaChecksum = replica.Checksum.IRODSChecksumString
+		}
+
+		version += fmt.Sprintf(":%d:%s:%d:%s", replica.ReplicaNumber, replica.ResourceHierarchy, replica.DataSize, replicaChecksum)
+	}
+
+	hash := sha1.Sum([]byte(version))
+	return hex.EncodeToString(hash[:])
+}
+
+// getBlockPath returns a relative path to the block file
+func (cache *DataCache) getBlockPath(cacheKey string, blockID int64) string {
+	return filepath.Join(cacheKey, strconv.FormatInt(blockID, 10))
+}
+
+// Validate returns a cache key for the replica of the entry, blocks of other versions of the replica are removed
+// replica is the info of the replica opened for read, blocks are keyed by the entry only if it is nil
+func (cache *DataCache) Validate(entry *Entry, replica *types.IRODSFileDescriptorInfo) (string, error) {
+	dataObjectDir := cache.getDataObjectDir(entry, replica)
+	versionKey := cache.getVersionKey(entry, replica)
+	cacheKey := filepath.Join(dataObjectDir, versionKey)
+
+	versionPaths, err := filepath.Glob(filepath.Join(cache.rootPath, dataObjectDir, "*"))
+	if err != nil {
+		return "", xerrors.Errorf("failed to list data cache dir for %s: %w", entry.Path, err)
+	}
+
+	for _, versionPath := range versionPaths {
+		if filepath.Base(versionPath) == versionKey {
+			continue
+		}
+
+		// stale
+		relPath, err := filepath.Rel(cache.rootPath, versionPath)
+		if err != nil {
+			continue
+		}
+
+		cache.removeBlocks(relPath)
+	}
+
+	return cacheKey, nil
+}
+
+// removeBlocks removes all blocks under the relative dir
+func (cache *DataCache) removeBlocks(relDirPath string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	prefix := relDirPath + string(filepath.Separator)
+	for blockPath, element := range cache.blocks {
+		if strings.HasPrefix(blockPath, prefix) {
+			cache.removeElement(element)
+		}
+	}
+
+	os.RemoveAll(filepath.Join(cache.rootPath, relDirPath))
+}
+
+// GetBlock returns the block data, returns nil if the block is not cached
+func (cache *DataCache) GetBlock(cacheKey string, blockID int64) []byte {
+	blockPath := cache.getBlockPath(cacheKey, blockID)
+
+	cache.mutex.Lock()
+	element, ok := cache.blocks[blockPath]
+	if ok {
+		cache.lru.MoveToFront(element)
+	}
+	cache.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(cache.rootPath, blockPath))
+	if err != nil {
+		// removed by others
+		cache.mutex.Lock()
+		if element, ok := cache.blocks[blockPath]; ok {
+			cache.removeElement(element)
+		}
+		cache.mutex.Unlock()
+		return nil
+	}
+
+	return data
+}
+
+// PutBlock stores the block data
+func (cache *DataCache) PutBlock(cacheKey string, blockID int64, data []byte) error {
+	if int64(len(data)) > cache.sizeMax {
+		// too large to cache
+		return nil
+	}
+
+	blockPath := cache.getBlockPath(cacheKey, blockID)
+	blockFullPath := filepath.Join(cache.rootPath, blockPath)
+
+	err := os.MkdirAll(filepath.Dir(blockFullPath), 0700)
+	if err != nil {
+		return xerrors.Errorf("failed to make data cache dir for %s: %w", blockPath, err)
+	}
+
+	// write to a temp file and rename, so readers do not see partial blocks
+	tempPath := blockFullPath + "." + xid.New().String()
+	err = os.WriteFile(tempPath, data, 0600)
+	if err != nil {
+		os.Remove(tempPath)
+		return xerrors.Errorf("failed to write data cache block %s: %w", blockPath, err)
+	}
+
+	err = os.Rename(tempPath, blockFullPath)
+	if err != nil {
+		os.Remove(tempPath)
+		return xerrors.Errorf("failed to rename data cache block %s: %w", blockPath, err)
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.blocks[blockPath]; ok {
+		block := element.Value.(*dataCacheBlock)
+		cache.size += int64(len(data)) - block.size
+		block.size = int64(len(data))
+		cache.lru.MoveToFront(element)
+	} else {
+		block := &dataCacheBlock{
+			path: blockPath,
+			size: int64(len(data)),
+		}
+
+		cache.blocks[blockPath] = cache.lru.PushFront(block)
+		cache.size += block.size
+	}
+
+	cache.evict()
+	return nil
+}
+
+// Clear removes all blocks
+func (cache *DataCache) Clear() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for _, element := range cache.blocks {
+		cache.removeElement(element)
+	}
+
+	entries, err := os.ReadDir(cache.rootPath)
+	if err != nil {
+		return xerrors.Errorf("failed to list data cache dir %s: %w", cache.rootPath, err)
+	}
+
+	for _, entry := range entries {
+		os.RemoveAll(filepath.Join(cache.rootPath, entry.Name()))
+	}
+
+	return nil
+}
+
+// evict removes least recently used blocks until the cache fits in its size limit
+func (cache *DataCache) evict() {
+	for cache.size > cache.sizeMax {
+		element := cache.lru.Back()
+		if element == nil {
+			return
+		}
+
+		cache.removeElement(element)
+	}
+}
+
+// removeElement removes the block from the cache and disk
+func (cache *DataCache) removeElement(element *list.Element) {
+	block := element.Value.(*dataCacheBlock)
+
+	cache.lru.Remove(element)
+	delete(cache.blocks, block.path)
+	cache.size -= block.size
+
+	os.Remove(filepath.Join(cache.rootPath, block.path))
+}
@@ -2,6 +2,7 @@ package fs
 
 import (
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/cyverse/go-irodsclient/irods/connection"
@@ -20,6 +21,14 @@ type FileHandle struct {
 	entry               *Entry
 	offset              int64
 	openMode            types.FileOpenMode
+	readAhead           *fileHandleReadAhead
+	writeBuffer         *fileHandleWriteBuffer
+	readerPool          *fileHandleReaderPool
+	sharedReplica       *fileHandleSharedReplica
+	replicaSecondary    bool
+	dataCacheKey        string
+	serverOffsetUnknown bool // true if the server-side offset is not the handle offset, e.g., after reads served from data cache
+	closed              bool // true if Close is called, the primary handle of a shared replica is closed later by the last writer
 	mutex               sync.Mutex
 }
 
@@ -81,11 +90,59 @@ func (handle *FileHandle) GetEntry() *Entry {
 	return handle.entry
 }
 
-// Close closes the file
+// Close closes the file, the handle can't be used after close
+// for a primary handle of a shared replica, the replica is finalized when all writers in this process are closed
 func (handle *FileHandle) Close() error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	err := handle.checkOpen()
+	if err != nil {
+		return err
+	}
+
+	handle.closed = true
+	handle.filesystem.fileHandleMap.Remove(handle.id)
+
+	// the file is closed even if flush fails, the flush error is returned
+	flushErr := handle.flushWriteBuffer()
+
+	// outstanding read requests must be received before the connection is used for other requests
+	handle.stopReadAhead()
+
+	if handle.replicaSecondary {
+		err = handle.closeSecondaryReplica()
+		if flushErr != nil {
+			return flushErr
+		}
+		return err
+	}
+
+	if handle.sharedReplica != nil && !handle.sharedReplica.requestPrimaryClose() {
+		// the last writer closes this handle
+		return flushErr
+	}
+
+	err = handle.closeHandle()
+	if flushErr != nil {
+		return flushErr
+	}
+
+	return err
+}
+
+// checkOpen returns an error if the handle is closed, must be called with the mutex locked
+func (handle *FileHandle) checkOpen() error {
+	if handle.closed {
+		return xerrors.Errorf("file handle for %s is already closed", handle.entry.Path)
+	}
+	return nil
+}
+
+// closeHandle closes the file and returns the connection
+func (handle *FileHandle) closeHandle() error {
+	handle.stopReadAhead()
+
 	if handle.irodsFileLockHandle != nil {
 		// unlock if locked
 		err := irods_fs.UnlockDataObject(handle.connection, handle.irodsFileLockHandle)
@@ -98,6 +155,10 @@ func (handle *FileHandle) Close() error {
 
 	defer handle.filesystem.ioSession.ReturnConnection(handle.connection)
 
+	if handle.readerPool != nil {
+		handle.readerPool.releaseAll()
+	}
+
 	err := irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
 	handle.filesystem.fileHandleMap.Remove(handle.id)
 
@@ -109,17 +170,72 @@ func (handle *FileHandle) Close() error {
 	return err
 }
 
+// Stat returns information of the replica opened, such as replica number and physical path
+// DataSize is the catalog value at open time, buffered data is flushed first, so BytesWritten counts all writes
+// the current size of the file written via the handle is available from GetEntry().Size
+func (handle *FileHandle) Stat() (*types.IRODSFileDescriptorInfo, error) {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	err = handle.discardReadAhead()
+	if err != nil {
+		return nil, err
+	}
+
+	return irods_fs.GetFileDescriptorInfo(handle.connection, handle.irodsFileHandle)
+}
+
 // Seek moves file pointer
 func (handle *FileHandle) Seek(offset int64, whence int) (int64, error) {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return handle.offset, err
+	}
+
+	if handle.serverOffsetUnknown && types.Whence(whence) == types.SeekCur {
+		// server-side offset is not moved by reads served from data cache, seek with absolute offset
+		offset = handle.offset + offset
+		whence = int(types.SeekSet)
+	}
+
+	if handle.readAhead != nil && handle.readAhead.isActive() {
+		// server-side offset is ahead of the handle offset, seek with absolute offset
+		switch types.Whence(whence) {
+		case types.SeekCur:
+			offset = handle.offset + offset
+			whence = int(types.SeekSet)
+		}
+
+		if types.Whence(whence) == types.SeekSet && handle.readAhead.isBuffered(offset) {
+			// move within prefetched data
+			handle.offset = offset
+			return offset, nil
+		}
+
+		_, err = handle.readAhead.stop()
+		handle.readAhead.markRead(false)
+		if err != nil {
+			// the server-side offset is moved by outstanding requests drained
+			handle.serverOffsetUnknown = true
+			return handle.offset, err
+		}
+	}
+
 	newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.Whence(whence))
 	if err != nil {
 		return newOffset, err
 	}
 
 	handle.offset = newOffset
+	handle.serverOffsetUnknown = false
 	return newOffset, nil
 }
 
@@ -128,7 +244,17 @@ func (handle *FileHandle) Truncate(size int64) error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
-	err := irods_fs.TruncateDataObjectHandle(handle.connection, handle.irodsFileHandle, size)
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return err
+	}
+
+	err = handle.discardReadAhead()
+	if err != nil {
+		return err
+	}
+
+	err = irods_fs.TruncateDataObjectHandle(handle.connection, handle.irodsFileHandle, size)
 	if err != nil {
 		return err
 	}
@@ -137,52 +263,375 @@ func (handle *FileHandle) Truncate(size int64) error {
 }
 
 // Read reads the file, implements io.Reader.Read
+// it is not retried on transient failures, reopen the file to continue
+// if data cache is enabled, data is served from the cache when the file is opened with read only mode
 func (handle *FileHandle) Read(buffer []byte) (int, error) {
+	if len(handle.dataCacheKey) > 0 {
+		return handle.readCached(buffer)
+	}
+
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	err := handle.checkOpen()
+	if err != nil {
+		return 0, err
+	}
+
 	if !handle.IsReadMode() {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	readLen, err := irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
-	if readLen > 0 {
-		handle.offset += int64(readLen)
+	err = handle.flushWriteBuffer()
+	if err != nil {
+		return 0, err
 	}
 
-	// it is possible to return readLen + EOF
-	return readLen, err
+	if handle.readAhead != nil {
+		handle.readAhead.markRead(true)
+	}
+
+	return handle.read(buffer)
 }
 
 // ReadAt reads data from given offset
+// if the file is opened for parallel read, concurrent calls are served in parallel with additional handles
+// and it follows io.ReaderAt contract, returning an error when fewer bytes than len(buffer) are read
+// if data cache is enabled, data is served from the cache when the file is opened with read only mode
 func (handle *FileHandle) ReadAt(buffer []byte, offset int64) (int, error) {
+	handle.mutex.Lock()
+	err := handle.checkOpen()
+	handle.mutex.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(handle.dataCacheKey) > 0 {
+		return handle.readAtCached(buffer, offset)
+	}
+
+	if handle.readerPool != nil {
+		return handle.readAtParallel(buffer, offset)
+	}
+
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	return handle.readAt(buffer, offset)
+}
+
+// readCached reads data at the current offset via data cache
+// the server-side offset is not moved on cache hits, so it becomes unknown
+func (handle *FileHandle) readCached(buffer []byte) (int, error) {
+	handle.mutex.Lock()
+	err := handle.checkOpen()
+	offset := handle.offset
+	handle.mutex.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+
+	readLen, err := handle.readAtCached(buffer, offset)
+
+	handle.mutex.Lock()
+	handle.offset = offset + int64(readLen)
+	handle.serverOffsetUnknown = true
+	handle.mutex.Unlock()
+
+	return readLen, err
+}
+
+// readAtCached reads data from given offset via data cache, reads until buffer is full or EOF
+func (handle *FileHandle) readAtCached(buffer []byte, offset int64) (int, error) {
+	dataCache := handle.filesystem.dataCache
+	blockSize := int64(dataCache.GetBlockSize())
+	fileSize := handle.entry.Size
+
+	totalReadLen := 0
+	for totalReadLen < len(buffer) {
+		curOffset := offset + int64(totalReadLen)
+		if curOffset >= fileSize {
+			break
+		}
+
+		blockID := curOffset / blockSize
+		blockOffset := blockID * blockSize
+
+		block := dataCache.GetBlock(handle.dataCacheKey, blockID)
+		if block == nil {
+			blockLen := blockSize
+			if fileSize-blockOffset < blockLen {
+				blockLen = fileSize - blockOffset
+			}
+
+			block = make([]byte, blockLen)
+			readLen, err := handle.readAtFull(block, blockOffset)
+			if err != nil && err != io.EOF {
+				return totalReadLen, err
+			}
+
+			block = block[:readLen]
+			if readLen == int(blockLen) {
+				// caching is best effort
+				dataCache.PutBlock(handle.dataCacheKey, blockID, block)
+			}
+		}
+
+		if curOffset-blockOffset >= int64(len(block)) {
+			break
+		}
+
+		totalReadLen += copy(buffer[totalReadLen:], block[curOffset-blockOffset:])
+	}
+
+	if totalReadLen < len(buffer) {
+		return totalReadLen, io.EOF
+	}
+
+	return totalReadLen, nil
+}
+
+// readAtFull reads data from given offset without cache, reads until buffer is full or EOF
+func (handle *FileHandle) readAtFull(buffer []byte, offset int64) (int, error) {
+	if handle.readerPool != nil {
+		return handle.readAtParallel(buffer, offset)
+	}
+
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	return handle.readAtUntilFull(buffer, offset)
+}
+
+// readAtParallel reads data from given offset with the handle if it is not busy, or with an additional handle
+func (handle *FileHandle) readAtParallel(buffer []byte, offset int64) (int, error) {
+	if handle.mutex.TryLock() {
+		defer handle.mutex.Unlock()
+
+		return handle.readAtUntilFull(buffer, offset)
+	}
+
+	reader, err := handle.readerPool.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer handle.readerPool.release(reader)
+
+	return reader.readAt(buffer, offset)
+}
+
+// readAtUntilFull reads data from given offset with the handle, reads until buffer is full or EOF
+func (handle *FileHandle) readAtUntilFull(buffer []byte, offset int64) (int, error) {
+	totalReadLen := 0
+	for totalReadLen < len(buffer) {
+		readLen, err := handle.readAt(buffer[totalReadLen:], offset+int64(totalReadLen))
+		totalReadLen += readLen
+
+		if err != nil {
+			return totalReadLen, err
+		}
+
+		if readLen == 0 {
+			return totalReadLen, io.EOF
+		}
+	}
+
+	return totalReadLen, nil
+}
+
+// readAt reads data from given offset with the handle
+func (handle *FileHandle) readAt(buffer []byte, offset int64) (int, error) {
 	if !handle.IsReadMode() {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	if handle.offset != offset {
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return 0, err
+	}
+
+	serverOffset := handle.offset
+	if handle.readAhead != nil {
+		handle.readAhead.markRead(handle.offset == offset)
+
+		if handle.readAhead.isBuffered(offset) {
+			// serve from prefetched data
+			handle.offset = offset
+			return handle.read(buffer)
+		}
+
+		if handle.readAhead.isActive() {
+			// outstanding read requests are received and discarded
+			prefetchedOffset, err := handle.readAhead.stop()
+			if err != nil {
+				// the server-side offset is moved by outstanding requests drained
+				handle.serverOffsetUnknown = true
+				return 0, err
+			}
+
+			serverOffset = prefetchedOffset
+		}
+	}
+
+	if serverOffset != offset || handle.serverOffsetUnknown {
 		newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.SeekSet)
 		if err != nil {
 			return 0, err
 		}
 
 		handle.offset = newOffset
+		handle.serverOffsetUnknown = false
 
 		if newOffset != offset {
 			return 0, xerrors.Errorf("failed to seek to %d", offset)
 		}
+	} else {
+		handle.offset = offset
+	}
+
+	return handle.read(buffer)
+}
+
+// read reads data at the current offset, prefetches blocks if reads are sequential
+// while prefetching, read requests for following blocks are kept outstanding, so the reader waits only for the block it needs
+func (handle *FileHandle) read(buffer []byte) (int, error) {
+	if handle.readAhead == nil {
+		err := handle.restoreServerOffset()
+		if err != nil {
+			return 0, err
+		}
+
+		readLen, err := irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
+		if readLen > 0 {
+			handle.offset += int64(readLen)
+		}
+
+		// it is possible to return readLen + EOF
+		return readLen, err
 	}
 
-	readLen, err := irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
-	if readLen > 0 {
-		handle.offset += int64(readLen)
+	if !handle.readAhead.isBuffered(handle.offset) {
+		if !handle.readAhead.isNext(handle.offset) {
+			// not prefetching, or the reader moved away from prefetched data
+			err := handle.discardReadAhead()
+			if err != nil {
+				return 0, err
+			}
+
+			err = handle.restoreServerOffset()
+			if err != nil {
+				return 0, err
+			}
+
+			if !handle.readAhead.shouldPrefetch(len(buffer)) {
+				readLen, err := irods_fs.ReadDataObject(handle.connection, handle.irodsFileHandle, buffer)
+				if readLen > 0 {
+					handle.offset += int64(readLen)
+				}
+
+				return readLen, err
+			}
+
+			err = handle.readAhead.start(handle, handle.offset)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		err := handle.readAhead.next()
+		if err != nil {
+			if err != io.EOF {
+				// outstanding requests are drained, they have moved the server-side offset
+				handle.readAhead.stop()
+				handle.serverOffsetUnknown = true
+			}
+			return 0, err
+		}
 	}
 
-	// it is possible to return readLen + EOF
-	return readLen, err
+	readLen := handle.readAhead.copyTo(buffer, handle.offset)
+	handle.offset += int64(readLen)
+	return readLen, nil
+}
+
+// discardReadAhead discards prefetched data and moves the server-side offset back to the handle offset
+func (handle *FileHandle) discardReadAhead() error {
+	if handle.readAhead == nil {
+		return nil
+	}
+
+	serverOffset, err := handle.readAhead.stop()
+	if err != nil {
+		// the server-side offset is moved by outstanding requests drained
+		handle.serverOffsetUnknown = true
+		return err
+	}
+
+	if serverOffset < 0 {
+		// read-ahead was not active
+		return nil
+	}
+
+	if serverOffset != handle.offset {
+		newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, handle.offset, types.SeekSet)
+		if err != nil {
+			handle.serverOffsetUnknown = true
+			return err
+		}
+
+		if newOffset != handle.offset {
+			handle.serverOffsetUnknown = true
+			return xerrors.Errorf("failed to seek to %d", handle.offset)
+		}
+	}
+
+	handle.serverOffsetUnknown = false
+	return nil
+}
+
+// restoreServerOffset moves the server-side offset to the handle offset if it is unknown
+func (handle *FileHandle) restoreServerOffset() error {
+	if !handle.serverOffsetUnknown {
+		return nil
+	}
+
+	newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, handle.offset, types.SeekSet)
+	if err != nil {
+		return err
+	}
+
+	if newOffset != handle.offset {
+		return xerrors.Errorf("failed to seek to %d", handle.offset)
+	}
+
+	handle.serverOffsetUnknown = false
+	return nil
+}
+
+// stopReadAhead stops prefetching without restoring the server-side offset, used when the file is closed
+func (handle *FileHandle) stopReadAhead() {
+	if handle.readAhead == nil {
+		return
+	}
+
+	// errors of outstanding reads do not matter as the file is closed
+	handle.readAhead.stop()
+}
+
+// SetReadAhead sets read-ahead for sequential reads, 0 window disables read-ahead
+func (handle *FileHandle) SetReadAhead(blockSize int, window int) error {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	err := handle.discardReadAhead()
+	if err != nil {
+		return err
+	}
+
+	handle.readAhead = newFileHandleReadAhead(blockSize, window)
+	return nil
 }
 
 // Write writes the file
@@ -190,11 +639,30 @@ func (handle *FileHandle) Write(data []byte) (int, error) {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	err := handle.checkOpen()
+	if err != nil {
+		return 0, err
+	}
+
 	if !handle.IsWriteMode() {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	err := irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+	err = handle.discardReadAhead()
+	if err != nil {
+		return 0, err
+	}
+
+	if handle.writeBuffer != nil {
+		return handle.writeBuffered(data, handle.offset)
+	}
+
+	err = handle.restoreServerOffset()
+	if err != nil {
+		return 0, err
+	}
+
+	err = irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
 	if err != nil {
 		return 0, err
 	}
@@ -202,8 +670,8 @@ func (handle *FileHandle) Write(data []byte) (int, error) {
 	handle.offset += int64(len(data))
 
 	// update
-	if handle.entry.Size < handle.offset+int64(len(data)) {
-		handle.entry.Size = handle.offset + int64(len(data))
+	if handle.entry.Size < handle.offset {
+		handle.entry.Size = handle.offset
 	}
 
 	return len(data), nil
@@ -214,24 +682,39 @@ func (handle *FileHandle) WriteAt(data []byte, offset int64) (int, error) {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	err := handle.checkOpen()
+	if err != nil {
+		return 0, err
+	}
+
 	if !handle.IsWriteMode() {
 		return 0, xerrors.Errorf("file is opened with %s mode", handle.openMode)
 	}
 
-	if handle.offset != offset {
+	err = handle.discardReadAhead()
+	if err != nil {
+		return 0, err
+	}
+
+	if handle.writeBuffer != nil {
+		return handle.writeBuffered(data, offset)
+	}
+
+	if handle.offset != offset || handle.serverOffsetUnknown {
 		newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.SeekSet)
 		if err != nil {
 			return 0, err
 		}
 
 		handle.offset = newOffset
+		handle.serverOffsetUnknown = false
 
 		if newOffset != offset {
 			return 0, xerrors.Errorf("failed to seek to %d", offset)
 		}
 	}
 
-	err := irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+	err = irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
 	if err != nil {
 		return 0, err
 	}
@@ -239,18 +722,99 @@ func (handle *FileHandle) WriteAt(data []byte, offset int64) (int, error) {
 	handle.offset += int64(len(data))
 
 	// update
-	if handle.entry.Size < handle.offset+int64(len(data)) {
-		handle.entry.Size = handle.offset + int64(len(data))
+	if handle.entry.Size < handle.offset {
+		handle.entry.Size = handle.offset
 	}
 
 	return len(data), nil
 }
 
+// writeBuffered buffers data written at the offset, full blocks are flushed asynchronously
+func (handle *FileHandle) writeBuffered(data []byte, offset int64) (int, error) {
+	// surface deferred error of previous flush
+	err := handle.writeBuffer.takeError()
+	if err != nil {
+		return 0, err
+	}
+
+	if !handle.writeBuffer.isAdjacent(offset) {
+		handle.writeBuffer.flushAsync(handle.writeBlock)
+	}
+
+	written := 0
+	for written < len(data) {
+		written += handle.writeBuffer.append(data[written:], offset+int64(written))
+
+		if handle.writeBuffer.isFull() {
+			handle.writeBuffer.flushAsync(handle.writeBlock)
+		}
+	}
+
+	handle.offset = offset + int64(len(data))
+
+	// update
+	if handle.entry.Size < handle.offset {
+		handle.entry.Size = handle.offset
+	}
+
+	return len(data), nil
+}
+
+// writeBlock writes a block flushed from write buffer
+func (handle *FileHandle) writeBlock(data []byte, offset int64, seek bool) error {
+	if seek {
+		newOffset, err := irods_fs.SeekDataObject(handle.connection, handle.irodsFileHandle, offset, types.SeekSet)
+		if err != nil {
+			return err
+		}
+
+		if newOffset != offset {
+			return xerrors.Errorf("failed to seek to %d", offset)
+		}
+	}
+
+	return irods_fs.WriteDataObject(handle.connection, handle.irodsFileHandle, data)
+}
+
+// flushWriteBuffer writes all buffered data, and moves the server-side offset to the handle offset
+// returns deferred error of previous flush if any
+func (handle *FileHandle) flushWriteBuffer() error {
+	if handle.writeBuffer == nil {
+		return nil
+	}
+
+	return handle.writeBuffer.flush(handle.writeBlock)
+}
+
+// Flush writes buffered data to the server
+func (handle *FileHandle) Flush() error {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	return handle.flushWriteBuffer()
+}
+
+// Sync writes buffered data to the server, same as Flush
+func (handle *FileHandle) Sync() error {
+	return handle.Flush()
+}
+
 // LockDataObject locks data object with write lock (exclusive)
 func (handle *FileHandle) LockDataObject(wait bool) error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	// asynchronous flushes use the connection, they must be done before the lock request
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return err
+	}
+
+	err = handle.discardReadAhead()
+	if err != nil {
+		return err
+	}
+
 	lockType := types.DataObjectLockTypeWrite
 	lockCommand := types.DataObjectLockCommandSetLock
 	if wait {
@@ -272,6 +836,17 @@ func (handle *FileHandle) RLockDataObject(wait bool) error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	// asynchronous flushes use the connection, they must be done before the lock request
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return err
+	}
+
+	err = handle.discardReadAhead()
+	if err != nil {
+		return err
+	}
+
 	lockType := types.DataObjectLockTypeRead
 	lockCommand := types.DataObjectLockCommandSetLock
 	if wait {
@@ -293,8 +868,19 @@ func (handle *FileHandle) UnlockDataObject() error {
 	handle.mutex.Lock()
 	defer handle.mutex.Unlock()
 
+	// buffered data must be written while the lock is held
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return err
+	}
+
+	err = handle.discardReadAhead()
+	if err != nil {
+		return err
+	}
+
 	if handle.irodsFileLockHandle != nil {
-		err := irods_fs.UnlockDataObject(handle.connection, handle.irodsFileLockHandle)
+		err = irods_fs.UnlockDataObject(handle.connection, handle.irodsFileLockHandle)
 		if err != nil {
 			return err
 		}
@@ -307,8 +893,20 @@ func (handle *FileHandle) UnlockDataObject() error {
 
 // preprocessRename should be called before the file is renamed
 func (handle *FileHandle) preprocessRename() error {
+	err := handle.flushWriteBuffer()
+	if err != nil {
+		return err
+	}
+
+	if handle.readerPool != nil {
+		handle.readerPool.invalidate()
+	}
+
+	// prefetched data is discarded, the file is reopened with the handle offset
+	handle.stopReadAhead()
+
 	// first, we need to close the file
-	err := irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
+	err = irods_fs.CloseDataObject(handle.connection, handle.irodsFileHandle)
 
 	if handle.IsWriteMode() {
 		handle.filesystem.invalidateCacheForFileUpdate(handle.entry.Path)
@@ -355,6 +953,10 @@ func (handle *FileHandle) postprocessRename(newPath string, newEntry *Entry) err
 		}
 	}
 
+	if handle.readerPool != nil {
+		handle.readerPool.setPath(newPath)
+	}
+
 	handle.irodsFileHandle = newHandle
 	handle.entry = newEntry
 	handle.openMode = newOpenMode
@@ -0,0 +1,126 @@
+package fs
+
+import (
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+)
+
+const (
+	// readAheadSequentialThreshold is the number of sequential reads required to start read-ahead
+	readAheadSequentialThreshold = 2
+)
+
+// fileHandleReadAhead prefetches blocks of a file handle for sequential reads
+// read requests for window blocks are kept outstanding ahead of the reader
+type fileHandleReadAhead struct {
+	blockSize       int
+	window          int
+	pipeline        *irods_fs.DataObjectReadPipeline
+	block           []byte // data of the block being consumed
+	blockOffset     int64  // file offset of the first byte in block
+	sequentialReads int
+}
+
+// newFileHandleReadAhead creates a new fileHandleReadAhead, returns nil if read-ahead is disabled
+func newFileHandleReadAhead(blockSize int, window int) *fileHandleReadAhead {
+	if blockSize <= 0 || window <= 0 {
+		return nil
+	}
+
+	return &fileHandleReadAhead{
+		blockSize:       blockSize,
+		window:          window,
+		pipeline:        nil, // started when sequential reads are detected
+		block:           nil,
+		blockOffset:     0,
+		sequentialReads: 0,
+	}
+}
+
+// getWindowSize returns total size of blocks prefetched ahead
+func (readAhead *fileHandleReadAhead) getWindowSize() int {
+	return readAhead.blockSize * readAhead.window
+}
+
+// isActive returns true if read requests are outstanding, the server-side offset is ahead of the reader
+func (readAhead *fileHandleReadAhead) isActive() bool {
+	return readAhead.pipeline != nil
+}
+
+// isBuffered returns true if data at the offset is in the current block
+func (readAhead *fileHandleReadAhead) isBuffered(offset int64) bool {
+	return len(readAhead.block) > 0 && readAhead.blockOffset <= offset && offset < readAhead.blockOffset+int64(len(readAhead.block))
+}
+
+// isNext returns true if the offset is the start of the block following the current block
+func (readAhead *fileHandleReadAhead) isNext(offset int64) bool {
+	return readAhead.isActive() && readAhead.blockOffset+int64(len(readAhead.block)) == offset
+}
+
+// copyTo copies buffered data at the offset to the given buffer
+func (readAhead *fileHandleReadAhead) copyTo(buffer []byte, offset int64) int {
+	if !readAhead.isBuffered(offset) {
+		return 0
+	}
+
+	start := int(offset - readAhead.blockOffset)
+	return copy(buffer, readAhead.block[start:])
+}
+
+// shouldPrefetch returns true if read-ahead should prefetch data for a read of the given size
+func (readAhead *fileHandleReadAhead) shouldPrefetch(size int) bool {
+	return readAhead.sequentialReads >= readAheadSequentialThreshold && size < readAhead.getWindowSize()
+}
+
+// markRead records a read, resets sequential read counter on a random access
+func (readAhead *fileHandleReadAhead) markRead(sequential bool) {
+	if sequential {
+		readAhead.sequentialReads++
+	} else {
+		readAhead.sequentialReads = 0
+	}
+}
+
+// start starts prefetching from the offset, which must be the server-side offset
+func (readAhead *fileHandleReadAhead) start(handle *FileHandle, offset int64) error {
+	pipeline, err := irods_fs.NewDataObjectReadPipeline(handle.connection, handle.irodsFileHandle, offset, readAhead.blockSize, readAhead.window)
+	if err != nil {
+		return err
+	}
+
+	readAhead.pipeline = pipeline
+	readAhead.block = nil
+	readAhead.blockOffset = offset
+	return nil
+}
+
+// next moves to the block following the current block, and requests another block to keep the window full
+func (readAhead *fileHandleReadAhead) next() error {
+	nextOffset := readAhead.blockOffset + int64(len(readAhead.block))
+
+	block, err := readAhead.pipeline.Next()
+	if err != nil {
+		readAhead.block = nil
+		readAhead.blockOffset = nextOffset
+		return err
+	}
+
+	readAhead.block = block
+	readAhead.blockOffset = nextOffset
+	return nil
+}
+
+// stop stops prefetching and discards prefetched data, returns the server-side offset
+// returns -1 if read-ahead was not active
+func (readAhead *fileHandleReadAhead) stop() (int64, error) {
+	readAhead.block = nil
+	readAhead.blockOffset = 0
+
+	if readAhead.pipeline == nil {
+		return -1, nil
+	}
+
+	pipeline := readAhead.pipeline
+	readAhead.pipeline = nil
+
+	return pipeline.Close()
+}
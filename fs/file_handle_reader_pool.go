@@ -0,0 +1,218 @@
+package fs
+
+import (
+	"io"
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// fileHandleReader is an additional read-only handle of a file, opened on another connection
+type fileHandleReader struct {
+	connection      *connection.IRODSConnection
+	irodsFileHandle *types.IRODSFileHandle
+	offset          int64
+	generation      int
+}
+
+// readAt reads data from given offset, reads until buffer is full or EOF
+func (reader *fileHandleReader) readAt(buffer []byte, offset int64) (int, error) {
+	if reader.offset != offset {
+		newOffset, err := irods_fs.SeekDataObject(reader.connection, reader.irodsFileHandle, offset, types.SeekSet)
+		if err != nil {
+			return 0, err
+		}
+
+		reader.offset = newOffset
+
+		if newOffset != offset {
+			return 0, xerrors.Errorf("failed to seek to %d", offset)
+		}
+	}
+
+	totalReadLen := 0
+	for totalReadLen < len(buffer) {
+		readLen, err := irods_fs.ReadDataObject(reader.connection, reader.irodsFileHandle, buffer[totalReadLen:])
+		if readLen > 0 {
+			totalReadLen += readLen
+			reader.offset += int64(readLen)
+		}
+
+		if err != nil {
+			return totalReadLen, err
+		}
+
+		if readLen == 0 {
+			return totalReadLen, io.EOF
+		}
+	}
+
+	return totalReadLen, nil
+}
+
+// fileHandleReaderPool manages additional read-only handles of a file for parallel ReadAt
+// handles are opened lazily on other pooled connections, up to maxReaders
+type fileHandleReaderPool struct {
+	filesystem *FileSystem
+	path       string
+	resource   string
+	maxReaders int
+	readers    []*fileHandleReader // idle readers
+	readerNum  int                 // number of opened readers, including readers in use
+	generation int                 // increased when the file is renamed, readers of old generation are closed on release
+	closed     bool
+	mutex      sync.Mutex
+	condition  *sync.Cond
+}
+
+// newFileHandleReaderPool creates a new fileHandleReaderPool
+func newFileHandleReaderPool(filesystem *FileSystem, path string, resource string, maxReaders int) *fileHandleReaderPool {
+	pool := &fileHandleReaderPool{
+		filesystem: filesystem,
+		path:       path,
+		resource:   resource,
+		maxReaders: maxReaders,
+		readers:    []*fileHandleReader{},
+		readerNum:  0,
+		generation: 0,
+		closed:     false,
+		mutex:      sync.Mutex{},
+	}
+
+	pool.condition = sync.NewCond(&pool.mutex)
+	return pool
+}
+
+// acquire returns an idle reader, opens a new one if possible, or waits until a reader is released
+func (pool *fileHandleReaderPool) acquire() (*fileHandleReader, error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	for {
+		if pool.closed {
+			return nil, xerrors.Errorf("file handle for %s is closed", pool.path)
+		}
+
+		if len(pool.readers) > 0 {
+			reader := pool.readers[len(pool.readers)-1]
+			pool.readers = pool.readers[:len(pool.readers)-1]
+			return reader, nil
+		}
+
+		if pool.readerNum < pool.maxReaders {
+			pool.readerNum++
+			path := pool.path
+			generation := pool.generation
+
+			// open outside of lock, as it takes a round trip
+			pool.mutex.Unlock()
+			reader, err := pool.open(path, generation)
+			pool.mutex.Lock()
+
+			if err == nil {
+				return reader, nil
+			}
+
+			pool.readerNum--
+			pool.condition.Signal()
+
+			if types.IsConnectionPoolFullError(err) && pool.readerNum > 0 {
+				// no more connections, share existing readers
+				pool.maxReaders = pool.readerNum
+				continue
+			}
+
+			return nil, err
+		}
+
+		pool.condition.Wait()
+	}
+}
+
+// release returns the reader to the pool, broken or outdated readers are closed
+func (pool *fileHandleReaderPool) release(reader *fileHandleReader) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if pool.closed || reader.generation != pool.generation || !reader.connection.IsConnected() {
+		pool.readerNum--
+		pool.close(reader)
+	} else {
+		pool.readers = append(pool.readers, reader)
+	}
+
+	pool.condition.Signal()
+}
+
+// open opens a new read-only handle of the file on a pooled connection
+func (pool *fileHandleReaderPool) open(path string, generation int) (*fileHandleReader, error) {
+	conn, err := pool.filesystem.ioSession.AcquireConnectionWithPriority(session.ConnectionPriorityLow)
+	if err != nil {
+		return nil, err
+	}
+
+	handle, offset, err := irods_fs.OpenDataObject(conn, path, pool.resource, string(types.FileOpenModeReadOnly))
+	if err != nil {
+		pool.filesystem.ioSession.ReturnConnection(conn)
+		return nil, err
+	}
+
+	return &fileHandleReader{
+		connection:      conn,
+		irodsFileHandle: handle,
+		offset:          offset,
+		generation:      generation,
+	}, nil
+}
+
+// close closes the reader and returns its connection
+func (pool *fileHandleReaderPool) close(reader *fileHandleReader) {
+	if !reader.connection.IsConnected() {
+		pool.filesystem.ioSession.DiscardConnection(reader.connection)
+		return
+	}
+
+	irods_fs.CloseDataObject(reader.connection, reader.irodsFileHandle)
+	pool.filesystem.ioSession.ReturnConnection(reader.connection)
+}
+
+// closeIdleReaders closes idle readers, readers in use are closed on release
+func (pool *fileHandleReaderPool) closeIdleReaders() {
+	for _, reader := range pool.readers {
+		pool.readerNum--
+		pool.close(reader)
+	}
+
+	pool.readers = []*fileHandleReader{}
+	pool.condition.Broadcast()
+}
+
+// invalidate closes readers before the file is renamed
+func (pool *fileHandleReaderPool) invalidate() {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.generation++
+	pool.closeIdleReaders()
+}
+
+// setPath makes the pool to open readers with the new path after the file is renamed
+func (pool *fileHandleReaderPool) setPath(newPath string) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.path = newPath
+}
+
+// releaseAll closes all readers
+func (pool *fileHandleReaderPool) releaseAll() {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	pool.closed = true
+	pool.closeIdleReaders()
+}
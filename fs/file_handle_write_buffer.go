@@ -0,0 +1,121 @@
+package fs
+
+import (
+	"sync"
+)
+
+// fileHandleWriteBlockFunc writes a block at the offset, seek is true if the server-side offset must be moved
+type fileHandleWriteBlockFunc func(data []byte, offset int64, seek bool) error
+
+// fileHandleWriteBuffer coalesces small and adjacent writes of a file handle into large blocks
+// full blocks are flushed asynchronously while following writes are buffered
+type fileHandleWriteBuffer struct {
+	size           int
+	buffer         []byte
+	bufferOffset   int64 // file offset of the first byte in buffer
+	bufferLen      int
+	spareBuffer    []byte // buffer being flushed, reused after the flush
+	serverOffset   int64  // offset of the server-side file descriptor, -1 if unknown
+	flushWaitGroup sync.WaitGroup
+	flushError     error // deferred error of asynchronous flush
+	mutex          sync.Mutex
+}
+
+// newFileHandleWriteBuffer creates a new fileHandleWriteBuffer, returns nil if write buffering is disabled
+func newFileHandleWriteBuffer(size int) *fileHandleWriteBuffer {
+	if size <= 0 {
+		return nil
+	}
+
+	return &fileHandleWriteBuffer{
+		size:           size,
+		buffer:         nil, // allocated lazily on first write
+		bufferOffset:   0,
+		bufferLen:      0,
+		spareBuffer:    nil,
+		serverOffset:   -1,
+		flushWaitGroup: sync.WaitGroup{},
+		flushError:     nil,
+	}
+}
+
+// isAdjacent returns true if data written at the offset can be appended to buffer
+func (writeBuffer *fileHandleWriteBuffer) isAdjacent(offset int64) bool {
+	return writeBuffer.bufferLen == 0 || writeBuffer.bufferOffset+int64(writeBuffer.bufferLen) == offset
+}
+
+// isFull returns true if buffer is full
+func (writeBuffer *fileHandleWriteBuffer) isFull() bool {
+	return writeBuffer.bufferLen >= writeBuffer.size
+}
+
+// append copies data written at the offset to buffer, returns the number of bytes copied
+// the offset must be adjacent to buffered data
+func (writeBuffer *fileHandleWriteBuffer) append(data []byte, offset int64) int {
+	if writeBuffer.buffer == nil {
+		writeBuffer.buffer = make([]byte, writeBuffer.size)
+	}
+
+	if writeBuffer.bufferLen == 0 {
+		writeBuffer.bufferOffset = offset
+	}
+
+	copied := copy(writeBuffer.buffer[writeBuffer.bufferLen:], data)
+	writeBuffer.bufferLen += copied
+	return copied
+}
+
+// flushAsync starts flushing buffered data in background
+// it waits for the previous flush, so only one flush is in flight at a time
+func (writeBuffer *fileHandleWriteBuffer) flushAsync(writeBlock fileHandleWriteBlockFunc) {
+	writeBuffer.flushWaitGroup.Wait()
+
+	if writeBuffer.bufferLen == 0 {
+		return
+	}
+
+	data := writeBuffer.buffer[:writeBuffer.bufferLen]
+	offset := writeBuffer.bufferOffset
+	seek := writeBuffer.serverOffset != offset
+
+	// swap buffers, so following writes can be buffered while flushing
+	writeBuffer.buffer, writeBuffer.spareBuffer = writeBuffer.spareBuffer, writeBuffer.buffer
+	writeBuffer.bufferOffset = 0
+	writeBuffer.bufferLen = 0
+	writeBuffer.serverOffset = offset + int64(len(data))
+
+	writeBuffer.flushWaitGroup.Add(1)
+	go func() {
+		defer writeBuffer.flushWaitGroup.Done()
+
+		err := writeBlock(data, offset, seek)
+		if err != nil {
+			writeBuffer.mutex.Lock()
+			if writeBuffer.flushError == nil {
+				writeBuffer.flushError = err
+			}
+			writeBuffer.serverOffset = -1
+			writeBuffer.mutex.Unlock()
+		}
+	}()
+}
+
+// flush writes all buffered data and waits for completion, returns deferred error if any
+// the server-side offset may be changed by the caller after flush, so it becomes unknown
+func (writeBuffer *fileHandleWriteBuffer) flush(writeBlock fileHandleWriteBlockFunc) error {
+	writeBuffer.flushAsync(writeBlock)
+	writeBuffer.flushWaitGroup.Wait()
+
+	writeBuffer.serverOffset = -1
+	return writeBuffer.takeError()
+}
+
+// takeError returns deferred error of asynchronous flush and clears it
+func (writeBuffer *fileHandleWriteBuffer) takeError() error {
+	writeBuffer.mutex.Lock()
+	defer writeBuffer.mutex.Unlock()
+
+	err := writeBuffer.flushError
+	writeBuffer.flushError = nil
+	return err
+}
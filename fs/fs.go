@@ -25,18 +25,31 @@ type FileSystem struct {
 	cache                *FileSystemCache
 	cachePropagation     *FileSystemCachePropagation
 	cacheEventHandlerMap *FilesystemCacheEventHandlerMap
+	cacheRefresher       *fileSystemCacheRefresher
 	fileHandleMap        *FileHandleMap
+	dataCache            *DataCache
 }
 
 // NewFileSystem creates a new FileSystem
 func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*FileSystem, error) {
+	var dataCache *DataCache
+	if len(config.DataCacheRootPath) > 0 {
+		var err error
+		dataCache, err = NewDataCache(config.DataCacheRootPath, config.DataCacheBlockSize, config.DataCacheSizeMax)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ioSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(ioSessionConfig)
 	ioSession, err := session.NewIRODSSession(account, ioSessionConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(metaSessionConfig)
 	metaSession, err := session.NewIRODSSession(account, metaSessionConfig)
 	if err != nil {
 		return nil, err
@@ -55,7 +68,12 @@ func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*File
 	ioSession.SetTransactionFailureHandler(ioTransactionFailureHandler)
 	metaSession.SetTransactionFailureHandler(metaTransactionFailureHandler)
 
-	cache := NewFileSystemCache(config.CacheTimeout, config.CacheCleanupTime, config.CacheTimeoutSettings, config.InvalidateParentEntryCacheImmediately)
+	cache := NewFileSystemCacheWithBackend(config.CacheTimeout, config.CacheCleanupTime, config.CacheTimeoutSettings, config.InvalidateParentEntryCacheImmediately, config.CacheBackend)
+	for cacheName, cacheLimit := range config.CacheLimits {
+		cache.SetCacheLimit(cacheName, cacheLimit)
+	}
+	cache.SetStaleGracePeriod(config.CacheStaleGracePeriod)
+	cache.SetBackendScope(GetFileSystemCacheBackendScope(account))
 
 	fs := &FileSystem{
 		id:                   xid.New().String(), // generate a new ID
@@ -65,7 +83,9 @@ func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*File
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		cacheRefresher:       newFileSystemCacheRefresher(),
 		fileHandleMap:        NewFileHandleMap(),
+		dataCache:            dataCache,
 	}
 
 	cachePropagation := NewFileSystemCachePropagation(fs)
@@ -76,13 +96,24 @@ func NewFileSystem(account *types.IRODSAccount, config *FileSystemConfig) (*File
 
 // NewFileSystemWithAddressResolver creates a new FileSystem
 func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileSystemConfig, addressResolver session.AddressResolver) (*FileSystem, error) {
+	var dataCache *DataCache
+	if len(config.DataCacheRootPath) > 0 {
+		var err error
+		dataCache, err = NewDataCache(config.DataCacheRootPath, config.DataCacheBlockSize, config.DataCacheSizeMax)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ioSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(ioSessionConfig)
 	ioSession, err := session.NewIRODSSessionWithAddressResolver(account, ioSessionConfig, addressResolver)
 	if err != nil {
 		return nil, err
 	}
 
 	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(metaSessionConfig)
 	metaSession, err := session.NewIRODSSessionWithAddressResolver(account, metaSessionConfig, addressResolver)
 	if err != nil {
 		return nil, err
@@ -101,7 +132,12 @@ func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileS
 	ioSession.SetTransactionFailureHandler(ioTransactionFailureHandler)
 	metaSession.SetTransactionFailureHandler(metaTransactionFailureHandler)
 
-	cache := NewFileSystemCache(config.CacheTimeout, config.CacheCleanupTime, config.CacheTimeoutSettings, config.InvalidateParentEntryCacheImmediately)
+	cache := NewFileSystemCacheWithBackend(config.CacheTimeout, config.CacheCleanupTime, config.CacheTimeoutSettings, config.InvalidateParentEntryCacheImmediately, config.CacheBackend)
+	for cacheName, cacheLimit := range config.CacheLimits {
+		cache.SetCacheLimit(cacheName, cacheLimit)
+	}
+	cache.SetStaleGracePeriod(config.CacheStaleGracePeriod)
+	cache.SetBackendScope(GetFileSystemCacheBackendScope(account))
 
 	fs := &FileSystem{
 		id:                   xid.New().String(), // generate a new ID
@@ -111,7 +147,9 @@ func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileS
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		cacheRefresher:       newFileSystemCacheRefresher(),
 		fileHandleMap:        NewFileHandleMap(),
+		dataCache:            dataCache,
 	}
 
 	cachePropagation := NewFileSystemCachePropagation(fs)
@@ -124,12 +162,14 @@ func NewFileSystemWithAddressResolver(account *types.IRODSAccount, config *FileS
 func NewFileSystemWithDefault(account *types.IRODSAccount, applicationName string) (*FileSystem, error) {
 	config := NewFileSystemConfigWithDefault(applicationName)
 	ioSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, config.ConnectionMax, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(ioSessionConfig)
 	ioSession, err := session.NewIRODSSession(account, ioSessionConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(metaSessionConfig)
 	metaSession, err := session.NewIRODSSession(account, metaSessionConfig)
 	if err != nil {
 		return nil, err
@@ -145,6 +185,7 @@ func NewFileSystemWithDefault(account *types.IRODSAccount, applicationName strin
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		cacheRefresher:       newFileSystemCacheRefresher(),
 		fileHandleMap:        NewFileHandleMap(),
 	}
 
@@ -157,12 +198,14 @@ func NewFileSystemWithDefault(account *types.IRODSAccount, applicationName strin
 // NewFileSystemWithSessionConfig creates a new FileSystem with custom session configurations
 func NewFileSystemWithSessionConfig(account *types.IRODSAccount, sessConfig *session.IRODSSessionConfig, addressResolver session.AddressResolver) (*FileSystem, error) {
 	config := NewFileSystemConfigWithDefault(sessConfig.ApplicationName)
+	config.applySessionConfig(sessConfig)
 	ioSession, err := session.NewIRODSSessionWithAddressResolver(account, sessConfig, addressResolver)
 	if err != nil {
 		return nil, err
 	}
 
 	metaSessionConfig := session.NewIRODSSessionConfig(config.ApplicationName, config.ConnectionErrorTimeout, config.ConnectionInitNumber, config.ConnectionLifespan, config.OperationTimeout, config.ConnectionIdleTimeout, FileSystemConnectionMetaDefault, config.TCPBufferSize, config.StartNewTransaction)
+	config.applyToSessionConfig(metaSessionConfig)
 	metaSession, err := session.NewIRODSSessionWithAddressResolver(account, metaSessionConfig, addressResolver)
 	if err != nil {
 		return nil, err
@@ -178,6 +221,7 @@ func NewFileSystemWithSessionConfig(account *types.IRODSAccount, sessConfig *ses
 		metaSession:          metaSession,
 		cache:                cache,
 		cacheEventHandlerMap: NewFilesystemCacheEventHandlerMap(),
+		cacheRefresher:       newFileSystemCacheRefresher(),
 		fileHandleMap:        NewFileHandleMap(),
 	}
 
@@ -196,6 +240,7 @@ func (fs *FileSystem) Release() {
 
 	fs.cacheEventHandlerMap.Release()
 	fs.cachePropagation.Release()
+	fs.cacheRefresher.wait()
 
 	fs.ioSession.Release()
 	fs.metaSession.Release()
@@ -255,6 +300,7 @@ func (fs *FileSystem) GetMetrics() *metrics.IRODSMetrics {
 	newMetrics := &metrics.IRODSMetrics{}
 	newMetrics.Sum(ioMetrics)
 	newMetrics.Sum(metaMetrics)
+	newMetrics.Sum(fs.cache.GetMetrics())
 	return newMetrics
 }
 
@@ -274,6 +320,13 @@ func (fs *FileSystem) Stat(p string) (*Entry, error) {
 		return cachedEntry, nil
 	}
 
+	// serve a stale Entry while refreshing it in background
+	staleEntry := fs.cache.GetStaleEntryCache(irodsPath)
+	if staleEntry != nil {
+		fs.refreshEntryCache(staleEntry)
+		return staleEntry, nil
+	}
+
 	// check if a cached dir Entry for the given path exists
 	parentPath := path.Dir(irodsPath)
 	cachedDirEntryPaths := fs.cache.GetDirCache(parentPath)
@@ -766,7 +819,7 @@ func (fs *FileSystem) ReplicateFile(path string, resource string, update bool) e
 func (fs *FileSystem) OpenFile(path string, resource string, mode string) (*FileHandle, error) {
 	irodsPath := util.GetCorrectIRODSPath(path)
 
-	conn, err := fs.ioSession.AcquireConnection()
+	conn, err := fs.ioSession.AcquireConnectionWithPriority(session.ConnectionPriorityLow)
 	if err != nil {
 		return nil, err
 	}
@@ -812,17 +865,47 @@ func (fs *FileSystem) OpenFile(path string, resource string, mode string) (*File
 		entry:           entry,
 		offset:          offset,
 		openMode:        types.FileOpenMode(mode),
+		readAhead:       newFileHandleReadAhead(fs.config.ReadAheadBlockSize, fs.config.ReadAheadWindow),
+		writeBuffer:     newFileHandleWriteBuffer(fs.config.WriteBufferSize),
+	}
+
+	if fs.dataCache != nil && openMode.IsReadOnly() && entry.ID > 0 {
+		// data cache is optional, read without cache on failure
+		// blocks are cached per replica, as replicas may have different contents
+		replicaInfo, err := irods_fs.GetFileDescriptorInfo(conn, handle)
+		if err == nil {
+			dataCacheKey, err := fs.dataCache.Validate(entry, replicaInfo)
+			if err == nil {
+				fileHandle.dataCacheKey = dataCacheKey
+			}
+		}
 	}
 
 	fs.fileHandleMap.Add(fileHandle)
 	return fileHandle, nil
 }
 
+// OpenFileForParallelRead opens a file for read, concurrent ReadAt calls are served in parallel
+// up to maxReaders additional read-only handles are opened lazily on other pooled connections
+func (fs *FileSystem) OpenFileForParallelRead(path string, resource string, maxReaders int) (*FileHandle, error) {
+	if maxReaders <= 0 {
+		return nil, xerrors.Errorf("invalid number of readers %d", maxReaders)
+	}
+
+	handle, err := fs.OpenFile(path, resource, string(types.FileOpenModeReadOnly))
+	if err != nil {
+		return nil, err
+	}
+
+	handle.readerPool = newFileHandleReaderPool(fs, handle.entry.Path, resource, maxReaders)
+	return handle, nil
+}
+
 // CreateFile opens a new file for write
 func (fs *FileSystem) CreateFile(path string, resource string, mode string) (*FileHandle, error) {
 	irodsPath := util.GetCorrectIRODSPath(path)
 
-	conn, err := fs.ioSession.AcquireConnection()
+	conn, err := fs.ioSession.AcquireConnectionWithPriority(session.ConnectionPriorityLow)
 	if err != nil {
 		return nil, err
 	}
@@ -863,6 +946,8 @@ func (fs *FileSystem) CreateFile(path string, resource string, mode string) (*Fi
 		entry:           entry,
 		offset:          offset,
 		openMode:        types.FileOpenMode(mode),
+		readAhead:       newFileHandleReadAhead(fs.config.ReadAheadBlockSize, fs.config.ReadAheadWindow),
+		writeBuffer:     newFileHandleWriteBuffer(fs.config.WriteBufferSize),
 	}
 
 	fs.fileHandleMap.Add(fileHandle)
@@ -875,13 +960,12 @@ func (fs *FileSystem) CreateFile(path string, resource string, mode string) (*Fi
 // getCollectionNoCache returns collection entry
 func (fs *FileSystem) getCollectionNoCache(path string) (*Entry, error) {
 	// retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	collection, err := irods_fs.GetCollection(conn, path)
+	var collection *types.IRODSCollection
+	err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var getErr error
+		collection, getErr = irods_fs.GetCollection(conn, path)
+		return getErr
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -910,6 +994,12 @@ func (fs *FileSystem) getCollection(path string) (*Entry, error) {
 		return cachedEntry, nil
 	}
 
+	staleEntry := fs.cache.GetStaleEntryCache(path)
+	if staleEntry != nil && staleEntry.Type == DirectoryEntry {
+		fs.refreshEntryCache(staleEntry)
+		return staleEntry, nil
+	}
+
 	// otherwise, retrieve it and add it to cache
 	return fs.getCollectionNoCache(path)
 }
@@ -971,37 +1061,113 @@ func (fs *FileSystem) getEntryFromDataObject(dataobject *types.IRODSDataObject)
 // listEntries lists entries in a collection
 func (fs *FileSystem) listEntries(collection *types.IRODSCollection) ([]*Entry, error) {
 	// check cache first
-	cachedEntries := []*Entry{}
-	useCached := false
-
-	cachedDirEntryPaths := fs.cache.GetDirCache(collection.Path)
-	if cachedDirEntryPaths != nil {
-		useCached = true
-		for _, cachedDirEntryPath := range cachedDirEntryPaths {
-			cachedEntry := fs.cache.GetEntryCache(cachedDirEntryPath)
-			if cachedEntry != nil {
-				cachedEntries = append(cachedEntries, cachedEntry)
-			} else {
-				useCached = false
-			}
-		}
+	cachedEntries := fs.getCachedDirEntries(collection.Path)
+	if cachedEntries != nil {
+		return cachedEntries, nil
 	}
 
-	if useCached {
-		// remove from nagative entry cache
-		for _, cachedEntry := range cachedEntries {
-			fs.cache.RemoveNegativeEntryCache(cachedEntry.Path)
-		}
-		return cachedEntries, nil
+	// serve stale entries while refreshing them in background
+	staleEntries := fs.getStaleDirEntries(collection.Path)
+	if staleEntries != nil {
+		fs.refreshDirCache(collection)
+		return staleEntries, nil
 	}
 
 	// otherwise, retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
+	var entries []*Entry
+	err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		entries, listErr = fs.listEntriesNoCache(conn, collection)
+		return listErr
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer fs.metaSession.ReturnConnection(conn)
 
+	return entries, nil
+}
+
+// getCachedDirEntries returns cached entries in a collection, returns nil if cache is not available
+func (fs *FileSystem) getCachedDirEntries(path string) []*Entry {
+	cachedDirEntryPaths := fs.cache.GetDirCache(path)
+	if cachedDirEntryPaths == nil {
+		return nil
+	}
+
+	cachedEntries := []*Entry{}
+	for _, cachedDirEntryPath := range cachedDirEntryPaths {
+		cachedEntry := fs.cache.GetEntryCache(cachedDirEntryPath)
+		if cachedEntry == nil {
+			return nil
+		}
+
+		cachedEntries = append(cachedEntries, cachedEntry)
+	}
+
+	// remove from nagative entry cache
+	for _, cachedEntry := range cachedEntries {
+		fs.cache.RemoveNegativeEntryCache(cachedEntry.Path)
+	}
+	return cachedEntries
+}
+
+// getStaleDirEntries returns entries in a collection cached, including expired caches within stale grace period
+// returns nil if the dir cache is not expired or not available
+func (fs *FileSystem) getStaleDirEntries(path string) []*Entry {
+	staleDirEntryPaths := fs.cache.GetStaleDirCache(path)
+	if staleDirEntryPaths == nil {
+		return nil
+	}
+
+	staleEntries := []*Entry{}
+	for _, staleDirEntryPath := range staleDirEntryPaths {
+		staleEntry := fs.cache.GetEntryCache(staleDirEntryPath)
+		if staleEntry == nil {
+			staleEntry = fs.cache.GetStaleEntryCache(staleDirEntryPath)
+			if staleEntry == nil {
+				return nil
+			}
+		}
+
+		staleEntries = append(staleEntries, staleEntry)
+	}
+
+	return staleEntries
+}
+
+// refreshEntryCache refreshes the entry cache in background
+func (fs *FileSystem) refreshEntryCache(entry *Entry) {
+	fs.cacheRefresher.refresh("entry:"+entry.Path, func() {
+		var err error
+		if entry.Type == DirectoryEntry {
+			_, err = fs.getCollectionNoCache(entry.Path)
+		} else {
+			_, err = fs.getDataObjectNoCache(entry.Path)
+		}
+
+		if err != nil && types.IsFileNotFoundError(err) {
+			// removed by others
+			fs.cache.RemoveEntryCache(entry.Path)
+		}
+	})
+}
+
+// refreshDirCache refreshes the dir cache in background
+func (fs *FileSystem) refreshDirCache(collection *types.IRODSCollection) {
+	fs.cacheRefresher.refresh("dir:"+collection.Path, func() {
+		err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+			_, listErr := fs.listEntriesNoCache(conn, collection)
+			return listErr
+		})
+		if err != nil && types.IsFileNotFoundError(err) {
+			// removed by others
+			fs.cache.RemoveDirCache(collection.Path)
+		}
+	})
+}
+
+// listEntriesNoCache lists entries in a collection using the given connection and adds them to cache
+func (fs *FileSystem) listEntriesNoCache(conn *connection.IRODSConnection, collection *types.IRODSCollection) ([]*Entry, error) {
 	collections, err := irods_fs.ListSubCollections(conn, collection.Path)
 	if err != nil {
 		return nil, err
@@ -1099,13 +1265,12 @@ func (fs *FileSystem) getDataObjectNoCache(path string) (*Entry, error) {
 
 	collection := fs.getCollectionFromEntry(collectionEntry)
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	dataobject, err := irods_fs.GetDataObjectMasterReplica(conn, collection, util.GetIRODSPathFileName(path))
+	var dataobject *types.IRODSDataObject
+	err = fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var getErr error
+		dataobject, getErr = irods_fs.GetDataObjectMasterReplica(conn, collection, util.GetIRODSPathFileName(path))
+		return getErr
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1134,6 +1299,12 @@ func (fs *FileSystem) getDataObject(path string) (*Entry, error) {
 		return cachedEntry, nil
 	}
 
+	staleEntry := fs.cache.GetStaleEntryCache(path)
+	if staleEntry != nil && staleEntry.Type == FileEntry {
+		fs.refreshEntryCache(staleEntry)
+		return staleEntry, nil
+	}
+
 	// otherwise, retrieve it and add it to cache
 	return fs.getDataObjectNoCache(path)
 }
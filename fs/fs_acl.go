@@ -3,6 +3,7 @@ package fs
 import (
 	"fmt"
 
+	"github.com/cyverse/go-irodsclient/irods/connection"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
@@ -79,13 +80,12 @@ func (fs *FileSystem) ListDirACLs(path string) ([]*types.IRODSAccess, error) {
 	}
 
 	// otherwise, retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	accesses, err := irods_fs.ListCollectionAccesses(conn, irodsPath)
+	var accesses []*types.IRODSAccess
+	err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		accesses, listErr = irods_fs.ListCollectionAccesses(conn, irodsPath)
+		return listErr
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -151,12 +151,6 @@ func (fs *FileSystem) ListFileACLs(path string) ([]*types.IRODSAccess, error) {
 	}
 
 	// otherwise, retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
 	collectionEntry, err := fs.getCollection(util.GetIRODSPathDirname(irodsPath))
 	if err != nil {
 		return nil, err
@@ -164,7 +158,12 @@ func (fs *FileSystem) ListFileACLs(path string) ([]*types.IRODSAccess, error) {
 
 	collection := fs.getCollectionFromEntry(collectionEntry)
 
-	accesses, err := irods_fs.ListDataObjectAccesses(conn, collection, util.GetIRODSPathFileName(irodsPath))
+	var accesses []*types.IRODSAccess
+	err = fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		accesses, listErr = irods_fs.ListDataObjectAccesses(conn, collection, util.GetIRODSPathFileName(irodsPath))
+		return listErr
+	})
 	if err != nil {
 		return nil, err
 	}
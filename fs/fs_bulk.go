@@ -2,13 +2,16 @@ package fs
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/rs/xid"
 	"golang.org/x/xerrors"
 )
 
@@ -43,7 +46,11 @@ func (fs *FileSystem) DownloadFile(irodsPath string, resource string, localPath
 		}
 	}
 
-	return irods_fs.DownloadDataObject(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, callback)
+	// download can be restarted from the beginning on transient failures
+	retryCallback := getRetryTrackerCallBack(callback)
+	return fs.ioSession.Retry(func() error {
+		return irods_fs.DownloadDataObject(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, retryCallback)
+	})
 }
 
 // DownloadFileResumable downloads a file to local with support of transfer resume
@@ -77,7 +84,10 @@ func (fs *FileSystem) DownloadFileResumable(irodsPath string, resource string, l
 		}
 	}
 
-	return irods_fs.DownloadDataObjectResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, callback)
+	retryCallback := getRetryTrackerCallBack(callback)
+	return fs.ioSession.Retry(func() error {
+		return irods_fs.DownloadDataObjectResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, retryCallback)
+	})
 }
 
 // DownloadFileToBuffer downloads a file to buffer
@@ -93,7 +103,10 @@ func (fs *FileSystem) DownloadFileToBuffer(irodsPath string, resource string, bu
 		return xerrors.Errorf("cannot download a collection %s", irodsSrcPath)
 	}
 
-	return irods_fs.DownloadDataObjectToBuffer(fs.ioSession, irodsSrcPath, resource, buffer, srcStat.Size, callback)
+	retryCallback := getRetryTrackerCallBack(callback)
+	return fs.ioSession.Retry(func() error {
+		return irods_fs.DownloadDataObjectToBuffer(fs.ioSession, irodsSrcPath, resource, buffer, srcStat.Size, retryCallback)
+	})
 }
 
 // DownloadFileParallel downloads a file to local in parallel
@@ -127,7 +140,10 @@ func (fs *FileSystem) DownloadFileParallel(irodsPath string, resource string, lo
 		}
 	}
 
-	return irods_fs.DownloadDataObjectParallel(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, callback)
+	retryCallback := getRetryTrackerCallBack(callback)
+	return fs.ioSession.Retry(func() error {
+		return irods_fs.DownloadDataObjectParallel(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, retryCallback)
+	})
 }
 
 // DownloadFileParallelResumable downloads a file to local in parallel with support of transfer resume
@@ -161,7 +177,10 @@ func (fs *FileSystem) DownloadFileParallelResumable(irodsPath string, resource s
 		}
 	}
 
-	return irods_fs.DownloadDataObjectParallelResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, callback)
+	retryCallback := getRetryTrackerCallBack(callback)
+	return fs.ioSession.Retry(func() error {
+		return irods_fs.DownloadDataObjectParallelResumable(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, taskNum, retryCallback)
+	})
 }
 
 // DownloadFileRedirectToResource downloads a file from resource to local in parallel
@@ -195,7 +214,10 @@ func (fs *FileSystem) DownloadFileRedirectToResource(irodsPath string, resource
 		}
 	}
 
-	return irods_fs.DownloadDataObjectFromResourceServer(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, callback)
+	retryCallback := getRetryTrackerCallBack(callback)
+	return fs.ioSession.Retry(func() error {
+		return irods_fs.DownloadDataObjectFromResourceServer(fs.ioSession, irodsSrcPath, resource, localFilePath, srcStat.Size, retryCallback)
+	})
 }
 
 // UploadFile uploads a local file to irods
@@ -370,3 +392,193 @@ func (fs *FileSystem) UploadFileParallelRedirectToResource(localPath string, iro
 	fs.cachePropagation.PropagateFileCreate(irodsFilePath)
 	return nil
 }
+
+// UploadFileAtomic uploads a local file to irods via a hidden temporary data object in the same collection
+// the temporary data object is renamed to the target path after the transfer and optional checksum verification,
+// so a failed upload never leaves a truncated data object under the target path.
+// if taskNum is larger than 1, the file is uploaded in parallel.
+// if overwrite is false, it fails when the target path already exists.
+func (fs *FileSystem) UploadFileAtomic(localPath string, irodsPath string, resource string, taskNum int, replicate bool, overwrite bool, verifyChecksum bool, callback common.TrackerCallBack) error {
+	localSrcPath := util.GetCorrectLocalPath(localPath)
+	irodsDestPath := util.GetCorrectIRODSPath(irodsPath)
+
+	irodsFilePath := irodsDestPath
+
+	srcStat, err := os.Stat(localSrcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// file not exists
+			return xerrors.Errorf("failed to find a file for local path %s: %w", localSrcPath, types.NewFileNotFoundError(localSrcPath))
+		}
+		return err
+	}
+
+	if srcStat.IsDir() {
+		return xerrors.Errorf("failed to find a file for local path %s, the path is for a directory: %w", localSrcPath, types.NewFileNotFoundError(localSrcPath))
+	}
+
+	destExist := false
+	destStat, err := fs.Stat(irodsDestPath)
+	if err != nil {
+		if !types.IsFileNotFoundError(err) {
+			return err
+		}
+	} else {
+		switch destStat.Type {
+		case FileEntry:
+			destExist = true
+		case DirectoryEntry:
+			localFileName := filepath.Base(localSrcPath)
+			irodsFilePath = util.MakeIRODSPath(irodsDestPath, localFileName)
+			destExist = fs.ExistsFile(irodsFilePath)
+		default:
+			return xerrors.Errorf("unknown entry type %s", destStat.Type)
+		}
+	}
+
+	if destExist && !overwrite {
+		return xerrors.Errorf("failed to upload a file to %s: %w", irodsFilePath, types.NewFileAlreadyExistError(irodsFilePath))
+	}
+
+	irodsTempPath := getAtomicUploadTempPath(irodsFilePath, "part")
+
+	if taskNum > 1 {
+		err = irods_fs.UploadDataObjectParallel(fs.ioSession, localSrcPath, irodsTempPath, resource, taskNum, replicate, callback)
+	} else {
+		err = irods_fs.UploadDataObject(fs.ioSession, localSrcPath, irodsTempPath, resource, replicate, callback)
+	}
+
+	fs.invalidateCacheForFileCreate(irodsTempPath)
+
+	if err != nil {
+		return fs.abortAtomicUpload(irodsTempPath, err)
+	}
+
+	if verifyChecksum {
+		err = fs.verifyUploadChecksum(localSrcPath, irodsTempPath, resource)
+		if err != nil {
+			return fs.abortAtomicUpload(irodsTempPath, err)
+		}
+	}
+
+	irodsBackupPath := ""
+	if destExist {
+		// iRODS does not allow renaming over an existing data object
+		// move the existing data object aside, so it can be restored if the rename fails
+		irodsBackupPath = getAtomicUploadTempPath(irodsFilePath, "old")
+
+		err = fs.RenameFileToFile(irodsFilePath, irodsBackupPath)
+		if err != nil {
+			if !types.IsFileNotFoundError(err) {
+				err = xerrors.Errorf("failed to move the existing data object %s to %s: %w", irodsFilePath, irodsBackupPath, err)
+				return fs.abortAtomicUpload(irodsTempPath, err)
+			}
+
+			// removed by others in the meantime
+			irodsBackupPath = ""
+		}
+	}
+
+	err = fs.RenameFileToFile(irodsTempPath, irodsFilePath)
+	if err != nil {
+		err = xerrors.Errorf("failed to rename the temporary data object %s to %s: %w", irodsTempPath, irodsFilePath, err)
+
+		if len(irodsBackupPath) > 0 {
+			restoreErr := fs.RenameFileToFile(irodsBackupPath, irodsFilePath)
+			if restoreErr != nil {
+				// the target is gone, keep both the temporary and the backup data objects
+				return xerrors.Errorf("failed to restore the existing data object %s from %s, the uploaded data is kept in %s (%s): %w", irodsFilePath, irodsBackupPath, irodsTempPath, restoreErr.Error(), err)
+			}
+		}
+
+		return fs.abortAtomicUpload(irodsTempPath, err)
+	}
+
+	if len(irodsBackupPath) > 0 {
+		err = fs.RemoveFile(irodsBackupPath, true)
+		if err != nil && !types.IsFileNotFoundError(err) {
+			return xerrors.Errorf("uploaded a file to %s, but failed to remove the previous data object moved to %s: %w", irodsFilePath, irodsBackupPath, err)
+		}
+	}
+
+	return nil
+}
+
+// getRetryTrackerCallBack returns a callback reporting progress of a retried transfer
+// a retried transfer may start over, so progress is reported from bytes already transferred in previous attempts
+func getRetryTrackerCallBack(callback common.TrackerCallBack) common.TrackerCallBack {
+	if callback == nil {
+		return nil
+	}
+
+	// parallel transfers call it concurrently
+	mutex := sync.Mutex{}
+	var maxProcessed int64
+	return func(processed int64, total int64) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if processed < maxProcessed {
+			// catching up with previous attempts
+			return
+		}
+
+		maxProcessed = processed
+		callback(processed, total)
+	}
+}
+
+// getAtomicUploadTempPath returns a hidden temporary path with the suffix in the same collection
+func getAtomicUploadTempPath(irodsPath string, suffix string) string {
+	dir := util.GetIRODSPathDirname(irodsPath)
+	name := util.GetIRODSPathFileName(irodsPath)
+	return util.MakeIRODSPath(dir, fmt.Sprintf(".%s.%s.%s", name, xid.New().String(), suffix))
+}
+
+// abortAtomicUpload removes a temporary data object of atomic upload and returns the cause
+// must not be called once the target data object is moved aside without being restored
+func (fs *FileSystem) abortAtomicUpload(irodsTempPath string, cause error) error {
+	err := fs.removeAtomicUploadTempFile(irodsTempPath)
+	if err != nil {
+		return xerrors.Errorf("%w (%s)", cause, err.Error())
+	}
+	return cause
+}
+
+// removeAtomicUploadTempFile removes a temporary data object of atomic upload
+func (fs *FileSystem) removeAtomicUploadTempFile(irodsTempPath string) error {
+	err := fs.RemoveFile(irodsTempPath, true)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			// the upload failed before creating the data object
+			return nil
+		}
+		return xerrors.Errorf("failed to remove the temporary data object %s: %w", irodsTempPath, err)
+	}
+	return nil
+}
+
+// verifyUploadChecksum compares checksum of the uploaded data object with the local file
+func (fs *FileSystem) verifyUploadChecksum(localPath string, irodsPath string, resource string) error {
+	conn, err := fs.ioSession.AcquireConnection()
+	if err != nil {
+		return err
+	}
+	defer fs.ioSession.ReturnConnection(conn)
+
+	checksum, err := irods_fs.GetDataObjectChecksum(conn, irodsPath, resource)
+	if err != nil {
+		return xerrors.Errorf("failed to get checksum of data object %s: %w", irodsPath, err)
+	}
+
+	localChecksum, err := util.HashLocalFile(localPath, string(checksum.Algorithm))
+	if err != nil {
+		return xerrors.Errorf("failed to compute checksum of local file %s: %w", localPath, err)
+	}
+
+	if !bytes.Equal(checksum.Checksum, localChecksum) {
+		return xerrors.Errorf("checksum of data object %s does not match local file %s: %w", irodsPath, localPath, types.NewIRODSError(common.USER_CHKSUM_MISMATCH))
+	}
+
+	return nil
+}
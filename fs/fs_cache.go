@@ -58,8 +58,18 @@ func (fs *FileSystem) invalidateCacheForDirCreate(path string) {
 	parentPath := util.GetIRODSPathDirname(path)
 	parentDirEntries := fs.cache.GetDirCache(parentPath)
 	if parentDirEntries != nil {
-		parentDirEntries = append(parentDirEntries, path)
-		fs.cache.AddDirCache(parentPath, parentDirEntries)
+		exist := false
+		for _, parentDirEntry := range parentDirEntries {
+			if parentDirEntry == path {
+				exist = true
+				break
+			}
+		}
+
+		if !exist {
+			parentDirEntries = append(parentDirEntries, path)
+			fs.cache.AddDirCache(parentPath, parentDirEntries)
+		}
 	}
 
 	// send event
@@ -184,8 +194,18 @@ func (fs *FileSystem) invalidateCacheForFileCreate(path string) {
 	parentPath := util.GetIRODSPathDirname(path)
 	parentDirEntries := fs.cache.GetDirCache(parentPath)
 	if parentDirEntries != nil {
-		parentDirEntries = append(parentDirEntries, path)
-		fs.cache.AddDirCache(parentPath, parentDirEntries)
+		exist := false
+		for _, parentDirEntry := range parentDirEntries {
+			if parentDirEntry == path {
+				exist = true
+				break
+			}
+		}
+
+		if !exist {
+			parentDirEntries = append(parentDirEntries, path)
+			fs.cache.AddDirCache(parentPath, parentDirEntries)
+		}
 	}
 
 	// send event
@@ -0,0 +1,382 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	io_fs "io/fs"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// IOFS is an adapter that implements io/fs.FS, io/fs.ReadDirFS, io/fs.StatFS and io/fs.ReadFileFS on top of FileSystem
+// names given to IOFS are slash-separated paths relative to the root collection, as required by io/fs
+type IOFS struct {
+	filesystem *FileSystem
+	root       string
+}
+
+// NewIOFS creates a new IOFS rooted at the given collection
+func NewIOFS(filesystem *FileSystem, root string) *IOFS {
+	return &IOFS{
+		filesystem: filesystem,
+		root:       util.GetCorrectIRODSPath(root),
+	}
+}
+
+// GetIOFS returns an io/fs.FS view of the collection at root
+func (fs *FileSystem) GetIOFS(root string) *IOFS {
+	return NewIOFS(fs, root)
+}
+
+// GetRoot returns the root collection path
+func (fsys *IOFS) GetRoot() string {
+	return fsys.root
+}
+
+// getIRODSPath converts io/fs name to an iRODS path
+func (fsys *IOFS) getIRODSPath(op string, name string) (string, error) {
+	if !io_fs.ValidPath(name) {
+		return "", &io_fs.PathError{Op: op, Path: name, Err: io_fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return fsys.root, nil
+	}
+
+	return util.MakeIRODSPath(fsys.root, name), nil
+}
+
+// Open opens the named file or directory, implements io/fs.FS
+func (fsys *IOFS) Open(name string) (io_fs.File, error) {
+	irodsPath, err := fsys.getIRODSPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := fsys.filesystem.Stat(irodsPath)
+	if err != nil {
+		return nil, newIOFSPathError("open", name, err)
+	}
+
+	if entry.IsDir() {
+		return &IOFSDir{
+			fsys:  fsys,
+			name:  name,
+			entry: entry,
+		}, nil
+	}
+
+	handle, err := fsys.filesystem.OpenFile(irodsPath, "", string(types.FileOpenModeReadOnly))
+	if err != nil {
+		return nil, newIOFSPathError("open", name, err)
+	}
+
+	return &IOFSFile{
+		name:   name,
+		entry:  entry,
+		handle: handle,
+	}, nil
+}
+
+// Stat returns a FileInfo describing the named file, implements io/fs.StatFS
+func (fsys *IOFS) Stat(name string) (io_fs.FileInfo, error) {
+	irodsPath, err := fsys.getIRODSPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := fsys.filesystem.Stat(irodsPath)
+	if err != nil {
+		return nil, newIOFSPathError("stat", name, err)
+	}
+
+	return NewEntryFileInfo(entry), nil
+}
+
+// ReadDir reads the named directory and returns a list of directory entries sorted by filename, implements io/fs.ReadDirFS
+func (fsys *IOFS) ReadDir(name string) ([]io_fs.DirEntry, error) {
+	irodsPath, err := fsys.getIRODSPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fsys.filesystem.List(irodsPath)
+	if err != nil {
+		return nil, newIOFSPathError("readdir", name, err)
+	}
+
+	return getSortedDirEntries(entries), nil
+}
+
+// ReadFile reads the named file and returns its contents, implements io/fs.ReadFileFS
+func (fsys *IOFS) ReadFile(name string) ([]byte, error) {
+	irodsPath, err := fsys.getIRODSPath("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := fsys.filesystem.Stat(irodsPath)
+	if err != nil {
+		return nil, newIOFSPathError("readfile", name, err)
+	}
+
+	if entry.IsDir() {
+		return nil, &io_fs.PathError{Op: "readfile", Path: name, Err: xerrors.Errorf("is a directory")}
+	}
+
+	handle, err := fsys.filesystem.OpenFile(irodsPath, "", string(types.FileOpenModeReadOnly))
+	if err != nil {
+		return nil, newIOFSPathError("readfile", name, err)
+	}
+	defer handle.Close()
+
+	// entry size may be stale, read until EOF
+	buffer := bytes.Buffer{}
+	buffer.Grow(int(entry.Size))
+
+	_, err = buffer.ReadFrom(handle)
+	if err != nil {
+		return nil, newIOFSPathError("readfile", name, err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Sub returns an IOFS corresponding to the subtree rooted at dir, implements io/fs.SubFS
+func (fsys *IOFS) Sub(dir string) (io_fs.FS, error) {
+	irodsPath, err := fsys.getIRODSPath("sub", dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIOFS(fsys.filesystem, irodsPath), nil
+}
+
+// IOFSFile is an opened file returned by IOFS, implements io/fs.File, io.ReaderAt and io.Seeker
+// the file keeps its own offset for Read and Seek, as ReadAt must not move it while ReadAt of FileHandle does
+type IOFSFile struct {
+	name   string
+	entry  *Entry
+	handle *FileHandle
+	offset int64
+	mutex  sync.Mutex
+}
+
+// Stat returns FileInfo of the file
+func (file *IOFSFile) Stat() (io_fs.FileInfo, error) {
+	return NewEntryFileInfo(file.entry), nil
+}
+
+// Read reads the file, implements io.Reader
+func (file *IOFSFile) Read(buffer []byte) (int, error) {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	readLen, err := file.handle.ReadAt(buffer, file.offset)
+	file.offset += int64(readLen)
+
+	if err != nil && err != io.EOF {
+		return readLen, newIOFSPathError("read", file.name, err)
+	}
+	return readLen, err
+}
+
+// ReadAt reads the file at the given offset, implements io.ReaderAt
+func (file *IOFSFile) ReadAt(buffer []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, &io_fs.PathError{Op: "readat", Path: file.name, Err: io_fs.ErrInvalid}
+	}
+
+	// io.ReaderAt requires to fill the buffer fully unless an error occurs
+	totalReadLen := 0
+	for totalReadLen < len(buffer) {
+		readLen, err := file.handle.ReadAt(buffer[totalReadLen:], offset+int64(totalReadLen))
+		totalReadLen += readLen
+		if err != nil {
+			if err == io.EOF {
+				return totalReadLen, io.EOF
+			}
+			return totalReadLen, newIOFSPathError("readat", file.name, err)
+		}
+
+		if readLen == 0 {
+			return totalReadLen, io.EOF
+		}
+	}
+
+	return totalReadLen, nil
+}
+
+// Seek moves file pointer, implements io.Seeker
+func (file *IOFSFile) Seek(offset int64, whence int) (int64, error) {
+	file.mutex.Lock()
+	defer file.mutex.Unlock()
+
+	newOffset := offset
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		newOffset = file.offset + offset
+	case io.SeekEnd:
+		newOffset = file.entry.Size + offset
+	default:
+		return file.offset, &io_fs.PathError{Op: "seek", Path: file.name, Err: io_fs.ErrInvalid}
+	}
+
+	if newOffset < 0 {
+		return file.offset, &io_fs.PathError{Op: "seek", Path: file.name, Err: io_fs.ErrInvalid}
+	}
+
+	file.offset = newOffset
+	return newOffset, nil
+}
+
+// Close closes the file
+func (file *IOFSFile) Close() error {
+	err := file.handle.Close()
+	if err != nil {
+		return newIOFSPathError("close", file.name, err)
+	}
+	return nil
+}
+
+// IOFSDir is an opened directory returned by IOFS, implements io/fs.ReadDirFile
+type IOFSDir struct {
+	fsys    *IOFS
+	name    string
+	entry   *Entry
+	entries []io_fs.DirEntry
+	listed  bool
+	offset  int
+}
+
+// Stat returns FileInfo of the directory
+func (dir *IOFSDir) Stat() (io_fs.FileInfo, error) {
+	return NewEntryFileInfo(dir.entry), nil
+}
+
+// Read returns an error as the directory cannot be read
+func (dir *IOFSDir) Read(buffer []byte) (int, error) {
+	return 0, &io_fs.PathError{Op: "read", Path: dir.name, Err: xerrors.Errorf("is a directory")}
+}
+
+// ReadDir reads the contents of the directory, implements io/fs.ReadDirFile
+func (dir *IOFSDir) ReadDir(count int) ([]io_fs.DirEntry, error) {
+	if !dir.listed {
+		entries, err := dir.fsys.filesystem.List(dir.entry.Path)
+		if err != nil {
+			return nil, newIOFSPathError("readdir", dir.name, err)
+		}
+
+		dir.entries = getSortedDirEntries(entries)
+		dir.listed = true
+	}
+
+	remaining := len(dir.entries) - dir.offset
+	if count <= 0 {
+		dirEntries := dir.entries[dir.offset:]
+		dir.offset = len(dir.entries)
+		return dirEntries, nil
+	}
+
+	if remaining == 0 {
+		return nil, io.EOF
+	}
+
+	if count > remaining {
+		count = remaining
+	}
+
+	dirEntries := dir.entries[dir.offset : dir.offset+count]
+	dir.offset += count
+	return dirEntries, nil
+}
+
+// Close closes the directory
+func (dir *IOFSDir) Close() error {
+	return nil
+}
+
+// EntryFileInfo is an io/fs.FileInfo and io/fs.DirEntry for Entry
+type EntryFileInfo struct {
+	entry *Entry
+}
+
+// NewEntryFileInfo creates a new EntryFileInfo
+func NewEntryFileInfo(entry *Entry) *EntryFileInfo {
+	return &EntryFileInfo{
+		entry: entry,
+	}
+}
+
+// Name returns base name of the file
+func (info *EntryFileInfo) Name() string {
+	return info.entry.Name
+}
+
+// Size returns length in bytes
+func (info *EntryFileInfo) Size() int64 {
+	return info.entry.Size
+}
+
+// Mode returns file mode bits
+func (info *EntryFileInfo) Mode() io_fs.FileMode {
+	if info.entry.IsDir() {
+		return io_fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ModTime returns modification time
+func (info *EntryFileInfo) ModTime() time.Time {
+	return info.entry.ModifyTime
+}
+
+// IsDir returns true if the entry is for a directory
+func (info *EntryFileInfo) IsDir() bool {
+	return info.entry.IsDir()
+}
+
+// Sys returns underlying Entry
+func (info *EntryFileInfo) Sys() interface{} {
+	return info.entry
+}
+
+// Type returns type bits of the entry, implements io/fs.DirEntry
+func (info *EntryFileInfo) Type() io_fs.FileMode {
+	return info.Mode().Type()
+}
+
+// Info returns FileInfo of the entry, implements io/fs.DirEntry
+func (info *EntryFileInfo) Info() (io_fs.FileInfo, error) {
+	return info, nil
+}
+
+// getSortedDirEntries returns dir entries sorted by name
+func getSortedDirEntries(entries []*Entry) []io_fs.DirEntry {
+	dirEntries := make([]io_fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		dirEntries = append(dirEntries, NewEntryFileInfo(entry))
+	}
+
+	sort.Slice(dirEntries, func(i int, j int) bool {
+		return dirEntries[i].Name() < dirEntries[j].Name()
+	})
+
+	return dirEntries
+}
+
+// newIOFSPathError creates a *io/fs.PathError wrapping the given iRODS error
+// iRODS errors are categorized, so errors.Is(err, io/fs.ErrNotExist) works
+func newIOFSPathError(op string, name string, err error) error {
+	return &io_fs.PathError{Op: op, Path: name, Err: err}
+}
@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"github.com/cyverse/go-irodsclient/irods/connection"
 	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/irods/util"
@@ -22,16 +23,14 @@ func (fs *FileSystem) ListMetadata(path string) ([]*types.IRODSMeta, error) {
 	irodsCorrectPath := util.GetCorrectIRODSPath(path)
 
 	// otherwise, retrieve it and add it to cache
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
 	var metadataobjects []*types.IRODSMeta
 
 	if fs.ExistsDir(irodsCorrectPath) {
-		metadataobjects, err = irods_fs.ListCollectionMeta(conn, irodsCorrectPath)
+		err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+			var listErr error
+			metadataobjects, listErr = irods_fs.ListCollectionMeta(conn, irodsCorrectPath)
+			return listErr
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -43,7 +42,11 @@ func (fs *FileSystem) ListMetadata(path string) ([]*types.IRODSMeta, error) {
 
 		collection := fs.getCollectionFromEntry(collectionEntry)
 
-		metadataobjects, err = irods_fs.ListDataObjectMeta(conn, collection, util.GetIRODSPathFileName(irodsCorrectPath))
+		err = fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+			var listErr error
+			metadataobjects, listErr = irods_fs.ListDataObjectMeta(conn, collection, util.GetIRODSPathFileName(irodsCorrectPath))
+			return listErr
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -65,22 +68,16 @@ func (fs *FileSystem) AddMetadata(irodsPath string, attName string, attValue str
 		Units: attUnits,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
+	isDir := fs.ExistsDir(irodsCorrectPath)
 
-	if fs.ExistsDir(irodsCorrectPath) {
-		err = irods_fs.AddCollectionMeta(conn, irodsCorrectPath, metadata)
-		if err != nil {
-			return err
-		}
-	} else {
-		err = irods_fs.AddDataObjectMeta(conn, irodsCorrectPath, metadata)
-		if err != nil {
-			return err
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		if isDir {
+			return irods_fs.AddCollectionMeta(conn, irodsCorrectPath, metadata)
 		}
+		return irods_fs.AddDataObjectMeta(conn, irodsCorrectPath, metadata)
+	})
+	if err != nil {
+		return err
 	}
 
 	fs.cache.RemoveMetadataCache(irodsCorrectPath)
@@ -95,22 +92,16 @@ func (fs *FileSystem) DeleteMetadata(irodsPath string, avuid int64) error {
 		AVUID: avuid,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
+	isDir := fs.ExistsDir(irodsCorrectPath)
 
-	if fs.ExistsDir(irodsCorrectPath) {
-		err = irods_fs.DeleteCollectionMeta(conn, irodsCorrectPath, metadata)
-		if err != nil {
-			return err
-		}
-	} else {
-		err = irods_fs.DeleteDataObjectMeta(conn, irodsCorrectPath, metadata)
-		if err != nil {
-			return err
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		if isDir {
+			return irods_fs.DeleteCollectionMeta(conn, irodsCorrectPath, metadata)
 		}
+		return irods_fs.DeleteDataObjectMeta(conn, irodsCorrectPath, metadata)
+	})
+	if err != nil {
+		return err
 	}
 
 	fs.cache.RemoveMetadataCache(irodsCorrectPath)
@@ -126,22 +117,16 @@ func (fs *FileSystem) DeleteMetadataByName(irodsPath string, attName string) err
 		Name:  attName,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
+	isDir := fs.ExistsDir(irodsCorrectPath)
 
-	if fs.ExistsDir(irodsCorrectPath) {
-		err = irods_fs.DeleteCollectionMeta(conn, irodsCorrectPath, metadata)
-		if err != nil {
-			return err
-		}
-	} else {
-		err = irods_fs.DeleteDataObjectMeta(conn, irodsCorrectPath, metadata)
-		if err != nil {
-			return err
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		if isDir {
+			return irods_fs.DeleteCollectionMeta(conn, irodsCorrectPath, metadata)
 		}
+		return irods_fs.DeleteDataObjectMeta(conn, irodsCorrectPath, metadata)
+	})
+	if err != nil {
+		return err
 	}
 
 	fs.cache.RemoveMetadataCache(irodsCorrectPath)
@@ -156,13 +141,9 @@ func (fs *FileSystem) AddUserMetadata(user string, attName, attValue, attUnits s
 		Units: attUnits,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	err = irods_fs.AddUserMeta(conn, user, metadata)
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		return irods_fs.AddUserMeta(conn, user, metadata)
+	})
 	if err != nil {
 		return err
 	}
@@ -176,13 +157,9 @@ func (fs *FileSystem) DeleteUserMetadata(user string, avuid int64) error {
 		AVUID: avuid,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	err = irods_fs.DeleteUserMeta(conn, user, metadata)
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		return irods_fs.DeleteUserMeta(conn, user, metadata)
+	})
 	if err != nil {
 		return err
 	}
@@ -197,13 +174,9 @@ func (fs *FileSystem) DeleteUserMetadataByName(user string, attName string) erro
 		Name:  attName,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	err = irods_fs.DeleteUserMeta(conn, user, metadata)
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		return irods_fs.DeleteUserMeta(conn, user, metadata)
+	})
 	if err != nil {
 		return err
 	}
@@ -213,13 +186,12 @@ func (fs *FileSystem) DeleteUserMetadataByName(user string, attName string) erro
 
 // ListUserMetadata lists all user metadata
 func (fs *FileSystem) ListUserMetadata(user string) ([]*types.IRODSMeta, error) {
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	metadataobjects, err := irods_fs.ListUserMeta(conn, user)
+	var metadataobjects []*types.IRODSMeta
+	err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		metadataobjects, listErr = irods_fs.ListUserMeta(conn, user)
+		return listErr
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -235,13 +207,9 @@ func (fs *FileSystem) AddResourceMetadata(resource string, attName, attValue, at
 		Units: attUnits,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	err = irods_fs.AddResourceMeta(conn, resource, metadata)
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		return irods_fs.AddResourceMeta(conn, resource, metadata)
+	})
 	if err != nil {
 		return err
 	}
@@ -255,13 +223,9 @@ func (fs *FileSystem) DeleteResourceMetadata(resource string, avuid int64) error
 		AVUID: avuid,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	err = irods_fs.DeleteResourceMeta(conn, resource, metadata)
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		return irods_fs.DeleteResourceMeta(conn, resource, metadata)
+	})
 	if err != nil {
 		return err
 	}
@@ -276,13 +240,9 @@ func (fs *FileSystem) DeleteResourceMetadataByName(resource string, attName stri
 		Name:  attName,
 	}
 
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	err = irods_fs.DeleteResourceMeta(conn, resource, metadata)
+	err := fs.metaSession.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		return irods_fs.DeleteResourceMeta(conn, resource, metadata)
+	})
 	if err != nil {
 		return err
 	}
@@ -292,13 +252,12 @@ func (fs *FileSystem) DeleteResourceMetadataByName(resource string, attName stri
 
 // ListResourceMetadata lists all resource metadata
 func (fs *FileSystem) ListResourceMetadata(resource string) ([]*types.IRODSMeta, error) {
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
-
-	metadataobjects, err := irods_fs.ListResourceMeta(conn, resource)
+	var metadataobjects []*types.IRODSMeta
+	err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		metadataobjects, listErr = irods_fs.ListResourceMeta(conn, resource)
+		return listErr
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -308,13 +267,19 @@ func (fs *FileSystem) ListResourceMetadata(resource string) ([]*types.IRODSMeta,
 
 // searchEntriesByMeta searches entries by meta
 func (fs *FileSystem) searchEntriesByMeta(metaName string, metaValue string) ([]*Entry, error) {
-	conn, err := fs.metaSession.AcquireConnection()
-	if err != nil {
-		return nil, err
-	}
-	defer fs.metaSession.ReturnConnection(conn)
+	var collections []*types.IRODSCollection
+	var dataobjects []*types.IRODSDataObject
+
+	err := fs.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var searchErr error
+		collections, searchErr = irods_fs.SearchCollectionsByMeta(conn, metaName, metaValue)
+		if searchErr != nil {
+			return searchErr
+		}
 
-	collections, err := irods_fs.SearchCollectionsByMeta(conn, metaName, metaValue)
+		dataobjects, searchErr = irods_fs.SearchDataObjectsMasterReplicaByMeta(conn, metaName, metaValue)
+		return searchErr
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -330,11 +295,6 @@ func (fs *FileSystem) searchEntriesByMeta(metaName string, metaValue string) ([]
 		fs.cache.AddEntryCache(entry)
 	}
 
-	dataobjects, err := irods_fs.SearchDataObjectsMasterReplicaByMeta(conn, metaName, metaValue)
-	if err != nil {
-		return nil, err
-	}
-
 	for _, dataobject := range dataobjects {
 		if len(dataobject.Replicas) == 0 {
 			continue
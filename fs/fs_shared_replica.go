@@ -0,0 +1,248 @@
+package fs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/rs/xid"
+	"golang.org/x/xerrors"
+)
+
+// ReplicaAccessInfo is information to open a replica being written by a primary handle
+// it can be passed to other processes to let them write disjoint ranges of the same data object
+type ReplicaAccessInfo struct {
+	Path              string `json:"path"`
+	ReplicaToken      string `json:"replica_token"`
+	ResourceHierarchy string `json:"resource_hierarchy"`
+	WriterNum         int    `json:"writer_num"`
+	DataSize          int64  `json:"data_size"`
+}
+
+// fileHandleSharedReplica tracks writers of a shared replica in this process
+type fileHandleSharedReplica struct {
+	info                  *ReplicaAccessInfo
+	primary               *FileHandle
+	secondaryNum          int
+	primaryCloseRequested bool
+	mutex                 sync.Mutex
+}
+
+// addSecondary registers a secondary writer
+func (shared *fileHandleSharedReplica) addSecondary() error {
+	shared.mutex.Lock()
+	defer shared.mutex.Unlock()
+
+	if shared.primaryCloseRequested {
+		return xerrors.Errorf("primary handle for %s is closed", shared.info.Path)
+	}
+
+	shared.secondaryNum++
+	return nil
+}
+
+// removeSecondary unregisters a secondary writer, returns true if the primary handle must be closed by the caller
+func (shared *fileHandleSharedReplica) removeSecondary() bool {
+	shared.mutex.Lock()
+	defer shared.mutex.Unlock()
+
+	shared.secondaryNum--
+	return shared.primaryCloseRequested && shared.secondaryNum == 0
+}
+
+// requestPrimaryClose marks the primary handle as closed, returns true if no secondary writer is open
+func (shared *fileHandleSharedReplica) requestPrimaryClose() bool {
+	shared.mutex.Lock()
+	defer shared.mutex.Unlock()
+
+	shared.primaryCloseRequested = true
+	return shared.secondaryNum == 0
+}
+
+// CreateFileForSharedWrite creates a new file and opens a primary handle of its replica for shared write
+// secondary handles opened with OpenSharedWriter or OpenFileWithReplicaAccessInfo write disjoint ranges of the file in parallel
+// the replica is marked good when the primary handle is closed, after all secondary handles in this process are closed
+// secondary handles in other processes must be closed before the primary handle is closed
+func (fs *FileSystem) CreateFileForSharedWrite(path string, resource string, writerNum int, dataSize int64) (*FileHandle, error) {
+	irodsPath := util.GetCorrectIRODSPath(path)
+
+	conn, err := fs.ioSession.AcquireConnectionWithPriority(session.ConnectionPriorityLow)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conn.SupportParallelUpload() {
+		fs.ioSession.ReturnConnection(conn)
+		return nil, xerrors.Errorf("does not support shared replica write in current iRODS Version")
+	}
+
+	handle, err := irods_fs.OpenDataObjectForPutParallel(conn, irodsPath, resource, string(types.FileOpenModeWriteTruncate), common.OPER_TYPE_NONE, writerNum, dataSize)
+	if err != nil {
+		fs.ioSession.ReturnConnection(conn)
+		return nil, err
+	}
+
+	replicaToken, resourceHierarchy, err := irods_fs.GetReplicaAccessInfo(conn, handle)
+	if err != nil {
+		irods_fs.CloseDataObject(conn, handle)
+		fs.ioSession.ReturnConnection(conn)
+		return nil, err
+	}
+
+	entry := &Entry{
+		ID:                0,
+		Type:              FileEntry,
+		Name:              util.GetIRODSPathFileName(irodsPath),
+		Path:              irodsPath,
+		Owner:             fs.account.ClientUser,
+		Size:              0,
+		CreateTime:        time.Now(),
+		ModifyTime:        time.Now(),
+		CheckSumAlgorithm: types.ChecksumAlgorithmUnknown,
+		CheckSum:          nil,
+	}
+
+	// do not return connection here
+	fileHandle := &FileHandle{
+		id:              xid.New().String(),
+		filesystem:      fs,
+		connection:      conn,
+		irodsFileHandle: handle,
+		entry:           entry,
+		offset:          0,
+		openMode:        types.FileOpenModeWriteTruncate,
+		writeBuffer:     newFileHandleWriteBuffer(fs.config.WriteBufferSize),
+	}
+
+	fileHandle.sharedReplica = &fileHandleSharedReplica{
+		info: &ReplicaAccessInfo{
+			Path:              irodsPath,
+			ReplicaToken:      replicaToken,
+			ResourceHierarchy: resourceHierarchy,
+			WriterNum:         writerNum,
+			DataSize:          dataSize,
+		},
+		primary: fileHandle,
+	}
+
+	fs.fileHandleMap.Add(fileHandle)
+	fs.invalidateCacheForFileCreate(irodsPath)
+	fs.cachePropagation.PropagateFileCreate(irodsPath)
+
+	return fileHandle, nil
+}
+
+// OpenFileWithReplicaAccessInfo opens a secondary handle of a replica being written by a primary handle
+// this is used to write a shared replica from other processes, the handle must be closed before the primary handle
+func (fs *FileSystem) OpenFileWithReplicaAccessInfo(info *ReplicaAccessInfo) (*FileHandle, error) {
+	return fs.openSecondaryReplica(info, nil)
+}
+
+// openSecondaryReplica opens a secondary handle of a shared replica
+func (fs *FileSystem) openSecondaryReplica(info *ReplicaAccessInfo, shared *fileHandleSharedReplica) (*FileHandle, error) {
+	irodsPath := util.GetCorrectIRODSPath(info.Path)
+
+	conn, err := fs.ioSession.AcquireConnectionWithPriority(session.ConnectionPriorityLow)
+	if err != nil {
+		return nil, err
+	}
+
+	// open the file with write mode, to not seek to end
+	handle, offset, err := irods_fs.OpenDataObjectWithReplicaToken(conn, irodsPath, "", string(types.FileOpenModeWriteOnly), info.ReplicaToken, info.ResourceHierarchy, info.WriterNum, info.DataSize)
+	if err != nil {
+		fs.ioSession.ReturnConnection(conn)
+		return nil, err
+	}
+
+	entry := &Entry{
+		ID:                0,
+		Type:              FileEntry,
+		Name:              util.GetIRODSPathFileName(irodsPath),
+		Path:              irodsPath,
+		Owner:             fs.account.ClientUser,
+		Size:              0,
+		CreateTime:        time.Now(),
+		ModifyTime:        time.Now(),
+		CheckSumAlgorithm: types.ChecksumAlgorithmUnknown,
+		CheckSum:          nil,
+	}
+
+	// do not return connection here
+	fileHandle := &FileHandle{
+		id:               xid.New().String(),
+		filesystem:       fs,
+		connection:       conn,
+		irodsFileHandle:  handle,
+		entry:            entry,
+		offset:           offset,
+		openMode:         types.FileOpenModeWriteOnly,
+		writeBuffer:      newFileHandleWriteBuffer(fs.config.WriteBufferSize),
+		sharedReplica:    shared,
+		replicaSecondary: true,
+	}
+
+	fs.fileHandleMap.Add(fileHandle)
+	return fileHandle, nil
+}
+
+// GetReplicaAccessInfo returns information to open secondary handles of the shared replica
+func (handle *FileHandle) GetReplicaAccessInfo() (*ReplicaAccessInfo, error) {
+	if handle.sharedReplica == nil {
+		return nil, xerrors.Errorf("file %s is not opened for shared write", handle.entry.Path)
+	}
+
+	info := *handle.sharedReplica.info
+	return &info, nil
+}
+
+// OpenSharedWriter opens a secondary handle of the shared replica on another connection
+// the primary handle can be closed before secondary handles, then the replica is finalized when the last one is closed
+func (handle *FileHandle) OpenSharedWriter() (*FileHandle, error) {
+	if handle.sharedReplica == nil || handle.replicaSecondary {
+		return nil, xerrors.Errorf("file %s is not a primary handle for shared write", handle.entry.Path)
+	}
+
+	err := handle.sharedReplica.addSecondary()
+	if err != nil {
+		return nil, err
+	}
+
+	secondaryHandle, err := handle.filesystem.openSecondaryReplica(handle.sharedReplica.info, handle.sharedReplica)
+	if err != nil {
+		if handle.sharedReplica.removeSecondary() {
+			handle.closePrimaryReplica()
+		}
+		return nil, err
+	}
+
+	return secondaryHandle, nil
+}
+
+// closeSecondaryReplica closes a secondary handle of a shared replica
+// closes the primary handle too if it is the last writer and the primary handle has been closed
+func (handle *FileHandle) closeSecondaryReplica() error {
+	err := irods_fs.CloseDataObjectReplica(handle.connection, handle.irodsFileHandle)
+	handle.filesystem.ioSession.ReturnConnection(handle.connection)
+	handle.filesystem.fileHandleMap.Remove(handle.id)
+
+	if handle.sharedReplica != nil && handle.sharedReplica.removeSecondary() {
+		primaryErr := handle.sharedReplica.primary.closePrimaryReplica()
+		if err == nil {
+			err = primaryErr
+		}
+	}
+
+	return err
+}
+
+// closePrimaryReplica closes the primary handle of a shared replica, this marks the replica good
+func (handle *FileHandle) closePrimaryReplica() error {
+	handle.mutex.Lock()
+	defer handle.mutex.Unlock()
+
+	return handle.closeHandle()
+}
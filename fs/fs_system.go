@@ -25,3 +25,19 @@ func (fs *FileSystem) ListProcesses(address string, zone string) ([]*types.IRODS
 func (fs *FileSystem) ListAllProcesses() ([]*types.IRODSProcess, error) {
 	return fs.ListProcesses("", "")
 }
+
+// GetServerInfo returns information of the server, such as versions and the zone of the catalog server
+func (fs *FileSystem) GetServerInfo() (*types.IRODSServerInfo, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	serverInfo, err := irods_fs.GetServerInfo(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return serverInfo, nil
+}
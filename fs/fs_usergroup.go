@@ -118,3 +118,55 @@ func (fs *FileSystem) ListUsers() ([]*types.IRODSUser, error) {
 
 	return users, nil
 }
+
+// GetTemporaryPassword issues a temporary password of the user logged in
+func (fs *FileSystem) GetTemporaryPassword() (string, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return "", err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	password, err := irods_fs.GetTemporaryPassword(conn)
+	if err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+// GetTemporaryPasswordForOther issues a temporary password of other user, only rodsadmin can issue
+func (fs *FileSystem) GetTemporaryPasswordForOther(user string) (string, error) {
+	conn, err := fs.metaSession.AcquireConnection()
+	if err != nil {
+		return "", err
+	}
+	defer fs.metaSession.ReturnConnection(conn)
+
+	password, err := irods_fs.GetTemporaryPasswordForOther(conn, user)
+	if err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+// GetTemporaryAccount issues a temporary password of the user logged in and returns an account using it
+func (fs *FileSystem) GetTemporaryAccount() (*types.IRODSAccount, error) {
+	password, err := fs.GetTemporaryPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.account.GetTemporaryAccount("", password), nil
+}
+
+// GetTemporaryAccountForOther issues a temporary password of other user and returns an account using it, only rodsadmin can issue
+func (fs *FileSystem) GetTemporaryAccountForOther(user string) (*types.IRODSAccount, error) {
+	password, err := fs.GetTemporaryPasswordForOther(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.account.GetTemporaryAccount(user, password), nil
+}
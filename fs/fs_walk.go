@@ -0,0 +1,372 @@
+package fs
+
+import (
+	io_fs "io/fs"
+	"sort"
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+)
+
+const (
+	// WalkWorkerNumDefault is a default number of workers listing collections in parallel
+	WalkWorkerNumDefault = 5
+)
+
+// SkipDir is used as a return value from WalkFunc to indicate that the directory named in the call is to be skipped
+// it is the same value as io/fs.SkipDir
+var SkipDir = io_fs.SkipDir
+
+// WalkFunc is a function called by Walk for each entry visited
+// if listing a directory fails, the function is called a second time for the directory with the error
+// the function is never called concurrently
+type WalkFunc func(path string, entry *Entry, err error) error
+
+// WalkOptions defines options for Walk
+type WalkOptions struct {
+	// WorkerNum is the number of workers listing sibling collections in parallel,
+	// it is also the number of sibling collections listed ahead of being visited
+	WorkerNum int
+	// FlatQuery enumerates all collections and data objects under root with a single query per type,
+	// instead of listing collections one by one. Entries listed this way are not cached.
+	FlatQuery bool
+	// Sorted makes entries in a directory to be visited in lexical order
+	Sorted bool
+}
+
+// NewWalkOptionsWithDefault creates WalkOptions with default settings
+func NewWalkOptionsWithDefault() *WalkOptions {
+	return &WalkOptions{
+		WorkerNum: WalkWorkerNumDefault,
+		FlatQuery: false,
+		Sorted:    false,
+	}
+}
+
+// walkListing is a result of listing a collection
+type walkListing struct {
+	entries []*Entry
+	err     error
+	done    chan bool
+}
+
+// walker walks a tree
+type walker struct {
+	filesystem *FileSystem
+	options    *WalkOptions
+	fn         WalkFunc
+	lookahead  int // max number of sibling collections listed ahead
+	jobs       chan *walkJob
+	terminate  chan bool
+	wg         sync.WaitGroup
+}
+
+// walkJob is a request to list a collection
+type walkJob struct {
+	entry   *Entry
+	listing *walkListing
+}
+
+// Walk walks the file tree rooted at root, calling fn for each entry including root
+// next sibling collections are listed in parallel by a pool of workers ahead of descending, while fn is called in depth-first order,
+// each collection is descended right after fn is called for it, as filepath.WalkDir does
+func (fs *FileSystem) Walk(root string, fn WalkFunc, options *WalkOptions) error {
+	if options == nil {
+		options = NewWalkOptionsWithDefault()
+	}
+
+	irodsPath := util.GetCorrectIRODSPath(root)
+
+	rootEntry, err := fs.Stat(irodsPath)
+	if err != nil {
+		err = fn(irodsPath, nil, err)
+		if err == SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	err = fn(irodsPath, rootEntry, nil)
+	if err != nil {
+		if err == SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	if !rootEntry.IsDir() {
+		return nil
+	}
+
+	if options.FlatQuery {
+		return fs.walkFlat(rootEntry, fn, options)
+	}
+
+	w := fs.newWalker(fn, options)
+	defer w.release()
+
+	err = w.walk(rootEntry, w.submit(rootEntry))
+	if err == SkipDir {
+		return nil
+	}
+	return err
+}
+
+func (fs *FileSystem) newWalker(fn WalkFunc, options *WalkOptions) *walker {
+	workerNum := options.WorkerNum
+	if workerNum <= 0 {
+		workerNum = WalkWorkerNumDefault
+	}
+
+	w := &walker{
+		filesystem: fs,
+		options:    options,
+		fn:         fn,
+		lookahead:  workerNum,
+		jobs:       make(chan *walkJob, workerNum*10),
+		terminate:  make(chan bool),
+		wg:         sync.WaitGroup{},
+	}
+
+	w.wg.Add(workerNum)
+	for i := 0; i < workerNum; i++ {
+		go w.work()
+	}
+
+	return w
+}
+
+// release stops workers
+func (w *walker) release() {
+	close(w.terminate)
+	w.wg.Wait()
+}
+
+// work processes listing jobs
+func (w *walker) work() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.terminate:
+			return
+		case job := <-w.jobs:
+			job.listing.entries, job.listing.err = w.list(job.entry)
+			close(job.listing.done)
+		}
+	}
+}
+
+// list lists entries of a collection with an IO connection
+func (w *walker) list(entry *Entry) ([]*Entry, error) {
+	fs := w.filesystem
+
+	cachedEntries := fs.getCachedDirEntries(entry.Path)
+	if cachedEntries != nil {
+		return cachedEntries, nil
+	}
+
+	var entries []*Entry
+	err := fs.ioSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		entries, listErr = fs.listEntriesNoCache(conn, fs.getCollectionFromEntry(entry))
+		return listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// submit requests listing of a collection, returns listing that will be filled asynchronously
+func (w *walker) submit(entry *Entry) *walkListing {
+	listing := &walkListing{
+		done: make(chan bool),
+	}
+
+	w.jobs <- &walkJob{
+		entry:   entry,
+		listing: listing,
+	}
+
+	return listing
+}
+
+// walk visits entries of a collection recursively
+func (w *walker) walk(dirEntry *Entry, listing *walkListing) error {
+	<-listing.done
+
+	if listing.err != nil {
+		return w.fn(dirEntry.Path, dirEntry, listing.err)
+	}
+
+	entries := listing.entries
+	if w.options.Sorted {
+		entries = sortEntries(entries)
+	}
+
+	subDirs := []*Entry{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			subDirs = append(subDirs, entry)
+		}
+	}
+
+	// list next sub dirs in parallel ahead of descending, the window is topped up as each sub dir is visited or skipped
+	subDirListings := map[*Entry]*walkListing{}
+	nextSubDir := 0
+	fillLookahead := func() {
+		for nextSubDir < len(subDirs) && len(subDirListings) < w.lookahead {
+			subDir := subDirs[nextSubDir]
+			subDirListings[subDir] = w.submit(subDir)
+			nextSubDir++
+		}
+	}
+
+	fillLookahead()
+
+	for _, entry := range entries {
+		err := w.fn(entry.Path, entry, nil)
+
+		var listing *walkListing
+		if entry.IsDir() {
+			listing = subDirListings[entry]
+			delete(subDirListings, entry)
+
+			if err == nil || err == SkipDir {
+				fillLookahead()
+			}
+		}
+
+		if err != nil {
+			if err == SkipDir {
+				if entry.IsDir() {
+					// skip this dir
+					continue
+				}
+
+				// skip remaining entries in the dir
+				break
+			}
+			return err
+		}
+
+		if entry.IsDir() {
+			err = w.walk(entry, listing)
+			if err != nil && err != SkipDir {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkFlat visits all entries under root, enumerated with single queries
+func (fs *FileSystem) walkFlat(rootEntry *Entry, fn WalkFunc, options *WalkOptions) error {
+	var collections []*types.IRODSCollection
+	var dataobjects []*types.IRODSDataObject
+
+	err := fs.ioSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		collections, listErr = irods_fs.ListSubCollectionsRecursively(conn, rootEntry.Path)
+		if listErr != nil {
+			return listErr
+		}
+
+		dataobjects, listErr = irods_fs.ListDataObjectsMasterReplica(conn, fs.getCollectionFromEntry(rootEntry))
+		if listErr != nil {
+			return listErr
+		}
+
+		subDataobjects, listErr := irods_fs.ListDataObjectsMasterReplicaRecursively(conn, rootEntry.Path)
+		if listErr != nil {
+			return listErr
+		}
+
+		dataobjects = append(dataobjects, subDataobjects...)
+		return nil
+	})
+	if err != nil {
+		err = fn(rootEntry.Path, rootEntry, err)
+		if err == SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	// parent path => entries
+	children := map[string][]*Entry{}
+
+	for _, collection := range collections {
+		if collection.Path == rootEntry.Path {
+			continue
+		}
+
+		entry := fs.getEntryFromCollection(collection)
+		parentPath := util.GetIRODSPathDirname(entry.Path)
+		children[parentPath] = append(children[parentPath], entry)
+	}
+
+	for _, dataobject := range dataobjects {
+		if len(dataobject.Replicas) == 0 {
+			continue
+		}
+
+		entry := fs.getEntryFromDataObject(dataobject)
+		parentPath := util.GetIRODSPathDirname(entry.Path)
+		children[parentPath] = append(children[parentPath], entry)
+	}
+
+	err = walkFlatDir(rootEntry.Path, children, fn, options.Sorted)
+	if err == SkipDir {
+		return nil
+	}
+	return err
+}
+
+// walkFlatDir visits entries of a collection recursively, from pre-enumerated children
+func walkFlatDir(dirPath string, children map[string][]*Entry, fn WalkFunc, sorted bool) error {
+	entries := children[dirPath]
+	if sorted {
+		entries = sortEntries(entries)
+	}
+
+	for _, entry := range entries {
+		err := fn(entry.Path, entry, nil)
+		if err != nil {
+			if err == SkipDir {
+				if entry.IsDir() {
+					continue
+				}
+				break
+			}
+			return err
+		}
+
+		if entry.IsDir() {
+			err = walkFlatDir(entry.Path, children, fn, sorted)
+			if err != nil && err != SkipDir {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortEntries returns a copy of entries sorted by name
+func sortEntries(entries []*Entry) []*Entry {
+	sortedEntries := make([]*Entry, len(entries))
+	copy(sortedEntries, entries)
+
+	sort.Slice(sortedEntries, func(i int, j int) bool {
+		return sortedEntries[i].Name < sortedEntries[j].Name
+	})
+
+	return sortedEntries
+}
@@ -0,0 +1,433 @@
+package fs
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// FileSystemWatcherPollIntervalDefault is a default interval of polling changes
+	FileSystemWatcherPollIntervalDefault = 10 * time.Second
+)
+
+// fileSystemWatchState is a state of a watched collection
+type fileSystemWatchState struct {
+	// checkpoint is the modification time of the most recent change seen, in seconds
+	checkpoint time.Time
+	// seen keeps modification times of changes seen at checkpoint, as they are returned again by the next poll
+	seen map[string]time.Time
+}
+
+// FileSystemWatcher polls the catalog for changes made under watched collections by other clients
+// changes are sent as events to handlers added with FileSystem.AddCacheEventHandler, and stale caches are invalidated
+// removals are detected by comparing listings of modified collections with listings cached or seen before
+type FileSystemWatcher struct {
+	filesystem   *FileSystem
+	pollInterval time.Duration
+	watches      map[string]*fileSystemWatchState
+	snapshots    map[string]map[string]EntryType // collection path => entry path => entry type
+	lastError    error
+	terminateCh  chan bool
+	terminated   bool
+	started      bool
+	mutex        sync.Mutex
+	pollMutex    sync.Mutex
+}
+
+// NewFileSystemWatcher creates a new FileSystemWatcher
+func NewFileSystemWatcher(fs *FileSystem, pollInterval time.Duration) *FileSystemWatcher {
+	return &FileSystemWatcher{
+		filesystem:   fs,
+		pollInterval: pollInterval,
+		watches:      map[string]*fileSystemWatchState{},
+		snapshots:    map[string]map[string]EntryType{},
+		lastError:    nil,
+		terminateCh:  make(chan bool),
+		terminated:   false,
+		started:      false,
+	}
+}
+
+// NewFileSystemWatcherWithDefault creates a new FileSystemWatcher with default poll interval
+func NewFileSystemWatcherWithDefault(fs *FileSystem) *FileSystemWatcher {
+	return NewFileSystemWatcher(fs, FileSystemWatcherPollIntervalDefault)
+}
+
+// Watch starts watching changes made in the collection and collections under it after now
+func (watcher *FileSystemWatcher) Watch(path string) error {
+	return watcher.WatchSince(path, time.Now())
+}
+
+// WatchSince starts watching changes made in the collection and collections under it after the given time
+func (watcher *FileSystemWatcher) WatchSince(path string, since time.Time) error {
+	irodsPath := util.GetCorrectIRODSPath(path)
+
+	// take a snapshot to detect removals in the collection
+	_, err := watcher.refreshSnapshot(irodsPath)
+	if err != nil {
+		return xerrors.Errorf("failed to watch collection %s: %w", irodsPath, err)
+	}
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	watcher.watches[irodsPath] = &fileSystemWatchState{
+		checkpoint: since.Truncate(time.Second),
+		seen:       map[string]time.Time{},
+	}
+
+	return nil
+}
+
+// Unwatch stops watching changes made in the collection
+func (watcher *FileSystemWatcher) Unwatch(path string) {
+	irodsPath := util.GetCorrectIRODSPath(path)
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	delete(watcher.watches, irodsPath)
+	watcher.removeSnapshots(irodsPath)
+}
+
+// GetWatchedPaths returns paths of collections watched
+func (watcher *FileSystemWatcher) GetWatchedPaths() []string {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	paths := []string{}
+	for path := range watcher.watches {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// GetCheckpoint returns the modification time of the most recent change seen in the collection
+func (watcher *FileSystemWatcher) GetCheckpoint(path string) (time.Time, bool) {
+	irodsPath := util.GetCorrectIRODSPath(path)
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	state, ok := watcher.watches[irodsPath]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return state.checkpoint, true
+}
+
+// GetLastError returns the error occurred in the last poll in background, returns nil if succeeded
+func (watcher *FileSystemWatcher) GetLastError() error {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	return watcher.lastError
+}
+
+// Start starts polling in background
+func (watcher *FileSystemWatcher) Start() {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	if watcher.started || watcher.terminated {
+		return
+	}
+
+	watcher.started = true
+
+	go func() {
+		ticker := time.NewTicker(watcher.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watcher.terminateCh:
+				return
+			case <-ticker.C:
+				err := watcher.Poll()
+
+				watcher.mutex.Lock()
+				watcher.lastError = err
+				watcher.mutex.Unlock()
+			}
+		}
+	}()
+}
+
+// Release stops polling and releases resources
+func (watcher *FileSystemWatcher) Release() {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	if watcher.terminated {
+		return
+	}
+
+	watcher.terminated = true
+	close(watcher.terminateCh)
+
+	watcher.watches = map[string]*fileSystemWatchState{}
+	watcher.snapshots = map[string]map[string]EntryType{}
+}
+
+// Poll polls changes made in all watched collections once, and sends events
+func (watcher *FileSystemWatcher) Poll() error {
+	watcher.pollMutex.Lock()
+	defer watcher.pollMutex.Unlock()
+
+	for _, path := range watcher.GetWatchedPaths() {
+		err := watcher.poll(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// poll polls changes made in the watched collection
+func (watcher *FileSystemWatcher) poll(path string) error {
+	watcher.mutex.Lock()
+	state, ok := watcher.watches[path]
+	watcher.mutex.Unlock()
+
+	if !ok {
+		// unwatched
+		return nil
+	}
+
+	var collections []*types.IRODSCollection
+	var dataObjects []*types.IRODSDataObject
+	err := watcher.filesystem.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		collections, listErr = irods_fs.ListCollectionsModifiedSince(conn, path, state.checkpoint)
+		if listErr != nil {
+			return listErr
+		}
+
+		dataObjects, listErr = irods_fs.ListDataObjectsModifiedSince(conn, path, state.checkpoint)
+		return listErr
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to poll changes in collection %s: %w", path, err)
+	}
+
+	// parents are handled before children
+	sort.Slice(collections, func(i int, j int) bool {
+		return collections[i].Path < collections[j].Path
+	})
+
+	newCheckpoint := state.checkpoint
+	emitted := map[string]bool{}
+
+	isNew := func(entryPath string, modifyTime time.Time) bool {
+		if seenModifyTime, ok := state.seen[entryPath]; ok && seenModifyTime.Equal(modifyTime) {
+			return false
+		}
+
+		state.seen[entryPath] = modifyTime
+		if modifyTime.After(newCheckpoint) {
+			newCheckpoint = modifyTime
+		}
+		return true
+	}
+
+	// collections modified, but not created, may have entries removed
+	modifiedCollections := []string{}
+	previousSnapshots := map[string]map[string]EntryType{}
+
+	for _, collection := range collections {
+		if !isNew(collection.Path, collection.ModifyTime) {
+			continue
+		}
+
+		if collection.Path != path && !collection.CreateTime.Before(state.checkpoint) {
+			watcher.emit(collection.Path, FilesystemCacheDirCreateEvent)
+			emitted[collection.Path] = true
+			continue
+		}
+
+		modifiedCollections = append(modifiedCollections, collection.Path)
+		// take previous snapshots before events update dir caches
+		previousSnapshots[collection.Path] = watcher.getSnapshot(collection.Path)
+	}
+
+	for _, dataObject := range dataObjects {
+		if len(dataObject.Replicas) == 0 {
+			continue
+		}
+
+		replica := dataObject.Replicas[0]
+		if !isNew(dataObject.Path, replica.ModifyTime) {
+			continue
+		}
+
+		if !replica.CreateTime.Before(state.checkpoint) {
+			watcher.emit(dataObject.Path, FilesystemCacheFileCreateEvent)
+		} else {
+			watcher.emit(dataObject.Path, FilesystemCacheFileUpdateEvent)
+		}
+		emitted[dataObject.Path] = true
+	}
+
+	for _, collectionPath := range modifiedCollections {
+		err := watcher.pollCollection(collectionPath, previousSnapshots[collectionPath], emitted)
+		if err != nil {
+			return err
+		}
+	}
+
+	// changes seen before the new checkpoint will not be returned again
+	for entryPath, modifyTime := range state.seen {
+		if modifyTime.Before(newCheckpoint) {
+			delete(state.seen, entryPath)
+		}
+	}
+
+	watcher.mutex.Lock()
+	state.checkpoint = newCheckpoint
+	watcher.mutex.Unlock()
+
+	return nil
+}
+
+// pollCollection compares the listing of the modified collection with the previous one, and sends events for entries created or removed
+func (watcher *FileSystemWatcher) pollCollection(path string, previousSnapshot map[string]EntryType, emitted map[string]bool) error {
+	snapshot, err := watcher.refreshSnapshot(path)
+	if err != nil {
+		if types.IsFileNotFoundError(err) {
+			// removed after poll, its parent will report
+			return nil
+		}
+		return xerrors.Errorf("failed to list collection %s: %w", path, err)
+	}
+
+	if previousSnapshot == nil {
+		// unknown, can't compare
+		return nil
+	}
+
+	for entryPath, entryType := range previousSnapshot {
+		if _, ok := snapshot[entryPath]; ok {
+			continue
+		}
+
+		if entryType == DirectoryEntry {
+			watcher.emit(entryPath, FilesystemCacheDirRemoveEvent)
+		} else {
+			watcher.emit(entryPath, FilesystemCacheFileRemoveEvent)
+		}
+	}
+
+	// entries moved in keep their modification times, so they are not found by queries
+	for entryPath, entryType := range snapshot {
+		if _, ok := previousSnapshot[entryPath]; ok || emitted[entryPath] {
+			continue
+		}
+
+		if entryType == DirectoryEntry {
+			watcher.emit(entryPath, FilesystemCacheDirCreateEvent)
+		} else {
+			watcher.emit(entryPath, FilesystemCacheFileCreateEvent)
+		}
+	}
+
+	return nil
+}
+
+// getSnapshot returns the listing of the collection seen before, or cached
+// returns nil if not known
+func (watcher *FileSystemWatcher) getSnapshot(path string) map[string]EntryType {
+	watcher.mutex.Lock()
+	snapshot, ok := watcher.snapshots[path]
+	watcher.mutex.Unlock()
+
+	if ok {
+		return snapshot
+	}
+
+	cachedEntries := watcher.filesystem.getCachedDirEntries(path)
+	if cachedEntries == nil {
+		return nil
+	}
+
+	snapshot = map[string]EntryType{}
+	for _, cachedEntry := range cachedEntries {
+		snapshot[cachedEntry.Path] = cachedEntry.Type
+	}
+	return snapshot
+}
+
+// refreshSnapshot lists the collection from the server, and keeps the listing as a snapshot
+func (watcher *FileSystemWatcher) refreshSnapshot(path string) (map[string]EntryType, error) {
+	collectionEntry, err := watcher.filesystem.getCollectionNoCache(path)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := watcher.filesystem.getCollectionFromEntry(collectionEntry)
+
+	var entries []*Entry
+	err = watcher.filesystem.metaSession.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		var listErr error
+		entries, listErr = watcher.filesystem.listEntriesNoCache(conn, collection)
+		return listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]EntryType{}
+	for _, entry := range entries {
+		snapshot[entry.Path] = entry.Type
+	}
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	watcher.snapshots[path] = snapshot
+	return snapshot, nil
+}
+
+// removeSnapshots removes snapshots of the collection and collections under it
+func (watcher *FileSystemWatcher) removeSnapshots(path string) {
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for snapshotPath := range watcher.snapshots {
+		if snapshotPath == path || strings.HasPrefix(snapshotPath, prefix) {
+			delete(watcher.snapshots, snapshotPath)
+		}
+	}
+}
+
+// emit invalidates caches for the change and sends the event
+func (watcher *FileSystemWatcher) emit(path string, eventType FilesystemCacheEventType) {
+	switch eventType {
+	case FilesystemCacheFileCreateEvent:
+		watcher.filesystem.invalidateCacheForFileCreate(path)
+	case FilesystemCacheFileRemoveEvent:
+		watcher.filesystem.invalidateCacheForFileRemove(path)
+	case FilesystemCacheFileUpdateEvent:
+		watcher.filesystem.invalidateCacheForFileUpdate(path)
+	case FilesystemCacheDirCreateEvent:
+		watcher.filesystem.invalidateCacheForDirCreate(path)
+	case FilesystemCacheDirRemoveEvent:
+		watcher.filesystem.invalidateCacheForDirRemove(path, true)
+
+		watcher.mutex.Lock()
+		watcher.removeSnapshots(path)
+		watcher.mutex.Unlock()
+	default:
+		// unhandled
+	}
+}
@@ -4,7 +4,6 @@ go 1.18
 
 require (
 	github.com/hashicorp/go-rootcerts v1.0.2
-	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/rs/xid v1.3.0
 	github.com/sethvargo/go-password v0.2.0
 	github.com/sirupsen/logrus v1.7.0
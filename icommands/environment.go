@@ -91,6 +91,8 @@ func CreateIcommandsEnvironmentManagerFromIRODSAccount(account *types.IRODSAccou
 		manager.Environment.EncryptionAlgorithm = account.SSLConfiguration.EncryptionAlgorithm
 		manager.Environment.EncryptionSaltSize = account.SSLConfiguration.SaltSize
 		manager.Environment.EncryptionNumHashRounds = account.SSLConfiguration.HashRounds
+		manager.Environment.SSLCertificateChainFile = account.SSLConfiguration.CertificateChainFile
+		manager.Environment.SSLCertificateKeyFile = account.SSLConfiguration.CertificateKeyFile
 	}
 
 	manager.Password = account.Password
@@ -251,6 +253,30 @@ func (manager *ICommandsEnvironmentManager) SaveEnvironment() error {
 	return nil
 }
 
+// SavePAMToken saves pam token to password file (.irodsA), used when the pam token is refreshed
+func (manager *ICommandsEnvironmentManager) SavePAMToken(pamToken string) error {
+	if manager.Environment == nil {
+		return xerrors.Errorf("environment is not set")
+	}
+
+	manager.PamToken = pamToken
+
+	passwordFilePath := manager.GetPasswordFilePath()
+
+	// make dir first if not exist
+	dirpath := filepath.Dir(passwordFilePath)
+	err := os.MkdirAll(dirpath, 0700)
+	if err != nil {
+		return xerrors.Errorf("failed to make a dir %s: %w", dirpath, err)
+	}
+
+	err = EncodePasswordFile(passwordFilePath, pamToken, manager.UID)
+	if err != nil {
+		return xerrors.Errorf("failed to encode password file %s: %w", passwordFilePath, err)
+	}
+	return nil
+}
+
 // SaveSession saves session to a dir
 func (manager *ICommandsEnvironmentManager) SaveSession(processID int) error {
 	if manager.Session == nil {
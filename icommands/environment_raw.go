@@ -111,6 +111,9 @@ func (env *ICommandsEnvironment) ToIRODSAccount() *types.IRODSAccount {
 			EncryptionAlgorithm: env.EncryptionAlgorithm,
 			SaltSize:            env.EncryptionSaltSize,
 			HashRounds:          env.EncryptionNumHashRounds,
+
+			CertificateChainFile: env.SSLCertificateChainFile,
+			CertificateKeyFile:   env.SSLCertificateKeyFile,
 		},
 	}
 
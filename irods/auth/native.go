@@ -3,6 +3,7 @@ package auth
 import (
 	"crypto/md5"
 	"encoding/base64"
+	"encoding/hex"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
 )
@@ -32,3 +33,12 @@ func GenerateAuthResponse(challenge []byte, password string) string {
 	b64encodedPassword := base64.StdEncoding.EncodeToString(encodedPassword[:authResponseLen])
 	return b64encodedPassword
 }
+
+// GenerateClientSignature returns client signature from auth challenge, used in password obfuscation
+func GenerateClientSignature(challenge []byte) string {
+	if len(challenge) > 16 {
+		challenge = challenge[:16]
+	}
+
+	return hex.EncodeToString(challenge)
+}
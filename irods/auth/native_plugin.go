@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"golang.org/x/xerrors"
+)
+
+const (
+	// NativeAuthScheme is the scheme name of native auth plugin
+	NativeAuthScheme string = "native"
+)
+
+// NativeAuthPlugin is a client-side native auth plugin, authenticates with challenge-response
+type NativeAuthPlugin struct {
+	password    string
+	usePassword bool // true to use password given instead of the account's password
+}
+
+// NewNativeAuthPlugin creates a NativeAuthPlugin, authenticates with the account's password
+func NewNativeAuthPlugin() *NativeAuthPlugin {
+	return &NativeAuthPlugin{
+		password:    "",
+		usePassword: false,
+	}
+}
+
+// NewNativeAuthPluginWithPassword creates a NativeAuthPlugin, authenticates with the password given
+// e.g., the password generated by the server for PAM authentication
+func NewNativeAuthPluginWithPassword(password string) *NativeAuthPlugin {
+	return &NativeAuthPlugin{
+		password:    password,
+		usePassword: true,
+	}
+}
+
+// GetScheme returns the scheme name
+func (plugin *NativeAuthPlugin) GetScheme() string {
+	return NativeAuthScheme
+}
+
+// Execute runs the client-side operation
+func (plugin *NativeAuthPlugin) Execute(conn AuthPluginConnection, operation string, request AuthPluginContext) (AuthPluginContext, error) {
+	account := conn.GetAccount()
+
+	switch operation {
+	case AuthClientStart:
+		response := request.Copy()
+		response[AuthUserKey] = account.ProxyUser
+		response[AuthZoneKey] = account.ProxyZone
+		response[AuthNextOperationKey] = AuthClientAuthRequest
+		return response, nil
+	case AuthClientAuthRequest:
+		// server returns a challenge
+		response, err := requestServer(conn, request, AuthAgentAuthRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		response[AuthNextOperationKey] = AuthEstablishContext
+		return response, nil
+	case AuthEstablishContext:
+		challenge := []byte(request.GetString(AuthRequestResultKey))
+		if len(challenge) < challengeLen {
+			return nil, xerrors.Errorf("auth challenge must be %d bytes, but received %d bytes", challengeLen, len(challenge))
+		}
+
+		// save client signature
+		conn.SetClientSignature(GenerateClientSignature(challenge))
+
+		password := account.Password
+		if plugin.usePassword {
+			password = plugin.password
+		}
+
+		response := request.Copy()
+		response[AuthDigestKey] = GenerateAuthResponse(challenge, password)
+		response[AuthNextOperationKey] = AuthClientAuthResponse
+		return response, nil
+	case AuthClientAuthResponse:
+		response, err := requestServer(conn, request, AuthAgentAuthResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		response[AuthNextOperationKey] = AuthClientAuthFlowComplete
+		return response, nil
+	default:
+		return nil, xerrors.Errorf("unknown auth operation %s", operation)
+	}
+}
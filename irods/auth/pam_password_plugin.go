@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"strconv"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// PAMPasswordAuthScheme is the scheme name of pam password auth plugin
+	PAMPasswordAuthScheme string = "pam_password"
+
+	// pamPerformNativeAuth authenticates with the password generated by the server
+	pamPerformNativeAuth string = "perform_native_auth"
+)
+
+// PAMPasswordAuthPlugin is a client-side pam password auth plugin
+// the server authenticates the password with PAM and generates a password, then the native auth flow runs with it
+// if the account has a pam token generated before, the native auth flow runs with the token directly
+type PAMPasswordAuthPlugin struct{}
+
+// NewPAMPasswordAuthPlugin creates a PAMPasswordAuthPlugin
+func NewPAMPasswordAuthPlugin() *PAMPasswordAuthPlugin {
+	return &PAMPasswordAuthPlugin{}
+}
+
+// GetScheme returns the scheme name
+func (plugin *PAMPasswordAuthPlugin) GetScheme() string {
+	return PAMPasswordAuthScheme
+}
+
+// Execute runs the client-side operation
+func (plugin *PAMPasswordAuthPlugin) Execute(conn AuthPluginConnection, operation string, request AuthPluginContext) (AuthPluginContext, error) {
+	account := conn.GetAccount()
+
+	switch operation {
+	case AuthClientStart:
+		if !conn.IsSSL() {
+			return nil, xerrors.Errorf("connection should be using SSL")
+		}
+
+		response := request.Copy()
+		response[AuthUserKey] = account.ProxyUser
+		response[AuthZoneKey] = account.ProxyZone
+
+		if len(account.PamToken) > 0 {
+			response[AuthRequestResultKey] = account.PamToken
+			response[AuthNextOperationKey] = pamPerformNativeAuth
+		} else {
+			response[AuthNextOperationKey] = AuthClientAuthRequest
+		}
+		return response, nil
+	case AuthClientAuthRequest:
+		ttl := account.PamTTL
+		if ttl <= 0 {
+			ttl = 1
+		}
+
+		serverRequest := request.Copy()
+		serverRequest[AuthPasswordKey] = account.Password
+		serverRequest[AuthTTLKey] = strconv.Itoa(ttl)
+
+		// server returns a generated password
+		response, err := requestServer(conn, serverRequest, AuthAgentAuthRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		// do not pass the password to next operations
+		delete(response, AuthPasswordKey)
+
+		token := response.GetString(AuthRequestResultKey)
+		if len(token) == 0 {
+			return nil, xerrors.Errorf("server did not generate a password for pam authentication")
+		}
+
+		// save irods generated password for possible future use
+		account.PamToken = token
+
+		response[AuthNextOperationKey] = pamPerformNativeAuth
+		return response, nil
+	case pamPerformNativeAuth:
+		token := request.GetString(AuthRequestResultKey)
+
+		err := Authenticate(conn, NewNativeAuthPluginWithPassword(token), AuthPluginContext{})
+		if err != nil {
+			return nil, err
+		}
+
+		response := request.Copy()
+		delete(response, AuthRequestResultKey)
+		response[AuthNextOperationKey] = AuthClientAuthFlowComplete
+		return response, nil
+	default:
+		return nil, xerrors.Errorf("unknown auth operation %s", operation)
+	}
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// keys and operations of iRODS authentication framework, available from iRODS 4.3
+const (
+	AuthSchemeKey        string = "scheme"
+	AuthNextOperationKey string = "next_operation"
+	AuthUserKey          string = "user_name"
+	AuthZoneKey          string = "zone_name"
+	AuthPasswordKey      string = "a_pw"
+	AuthTTLKey           string = "a_ttl"
+	AuthRequestResultKey string = "request_result"
+	AuthDigestKey        string = "digest"
+
+	AuthClientStart            string = "auth_client_start"
+	AuthClientAuthRequest      string = "auth_client_auth_request"
+	AuthClientAuthResponse     string = "auth_client_auth_response"
+	AuthClientAuthFlowComplete string = "auth_client_auth_flow_complete"
+	AuthEstablishContext       string = "auth_establish_context"
+	AuthAgentAuthRequest       string = "auth_agent_auth_request"
+	AuthAgentAuthResponse      string = "auth_agent_auth_response"
+
+	// AuthMaxSteps is the max number of operations in an auth flow, to stop broken plugins from looping forever
+	AuthMaxSteps int = 100
+)
+
+// AuthPluginContext is a JSON object passed between operations of auth plugins
+type AuthPluginContext map[string]interface{}
+
+// Copy returns a shallow copy
+func (ctx AuthPluginContext) Copy() AuthPluginContext {
+	newCtx := AuthPluginContext{}
+	for k, v := range ctx {
+		newCtx[k] = v
+	}
+	return newCtx
+}
+
+// GetString returns a string value of the key, empty if it does not exist or is not a string
+func (ctx AuthPluginContext) GetString(key string) string {
+	if v, ok := ctx[key]; ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// AuthPluginConnection is a connection auth plugins authenticate
+type AuthPluginConnection interface {
+	GetAccount() *types.IRODSAccount
+	IsSSL() bool
+	// RequestAuthentication sends the request to the server-side auth plugin and returns its response
+	RequestAuthentication(request AuthPluginContext) (AuthPluginContext, error)
+	// SetClientSignature sets client signature used in password obfuscation
+	SetClientSignature(signature string)
+}
+
+// AuthPlugin is a client-side auth plugin of iRODS authentication framework
+// the flow starts with AuthClientStart and ends when an operation returns AuthClientAuthFlowComplete as next operation
+type AuthPlugin interface {
+	// GetScheme returns the scheme name of the server-side plugin, e.g., "native"
+	GetScheme() string
+	// Execute runs the client-side operation and returns the context having the next operation
+	Execute(conn AuthPluginConnection, operation string, request AuthPluginContext) (AuthPluginContext, error)
+}
+
+var (
+	authPlugins      = map[string]AuthPlugin{}
+	authPluginsMutex = sync.RWMutex{}
+)
+
+func init() {
+	RegisterAuthPlugin(NewNativeAuthPlugin())
+	RegisterAuthPlugin(NewPAMPasswordAuthPlugin())
+}
+
+// RegisterAuthPlugin registers an auth plugin, replaces the plugin registered with the same scheme
+func RegisterAuthPlugin(plugin AuthPlugin) {
+	authPluginsMutex.Lock()
+	defer authPluginsMutex.Unlock()
+
+	authPlugins[plugin.GetScheme()] = plugin
+}
+
+// GetAuthPlugin returns the auth plugin registered with the scheme
+func GetAuthPlugin(scheme string) (AuthPlugin, error) {
+	authPluginsMutex.RLock()
+	defer authPluginsMutex.RUnlock()
+
+	if plugin, ok := authPlugins[scheme]; ok {
+		return plugin, nil
+	}
+	return nil, xerrors.Errorf("failed to find auth plugin for scheme %s", scheme)
+}
+
+// HasAuthPlugin returns true if an auth plugin is registered with the scheme
+func HasAuthPlugin(scheme string) bool {
+	authPluginsMutex.RLock()
+	defer authPluginsMutex.RUnlock()
+
+	_, ok := authPlugins[scheme]
+	return ok
+}
+
+// GetAuthPluginScheme returns the scheme name of auth plugin for the auth scheme
+func GetAuthPluginScheme(authScheme types.AuthScheme) string {
+	switch authScheme {
+	case types.AuthSchemePAM:
+		return PAMPasswordAuthScheme
+	default:
+		return string(authScheme)
+	}
+}
+
+// Authenticate runs the auth flow of the plugin
+func Authenticate(conn AuthPluginConnection, plugin AuthPlugin, context AuthPluginContext) error {
+	ctx := context.Copy()
+	ctx[AuthSchemeKey] = plugin.GetScheme()
+	ctx[AuthNextOperationKey] = AuthClientStart
+
+	for step := 0; step < AuthMaxSteps; step++ {
+		operation := ctx.GetString(AuthNextOperationKey)
+		if len(operation) == 0 {
+			return xerrors.Errorf("next operation is not given in auth flow of scheme %s", plugin.GetScheme())
+		}
+
+		if operation == AuthClientAuthFlowComplete {
+			return nil
+		}
+
+		newCtx, err := plugin.Execute(conn, operation, ctx)
+		if err != nil {
+			return xerrors.Errorf("failed to run auth operation %s of scheme %s: %w", operation, plugin.GetScheme(), err)
+		}
+
+		ctx = newCtx
+	}
+
+	return xerrors.Errorf("auth flow of scheme %s did not complete in %d operations", plugin.GetScheme(), AuthMaxSteps)
+}
+
+// requestServer sends the request to the server-side plugin with the next operation
+func requestServer(conn AuthPluginConnection, request AuthPluginContext, serverOperation string) (AuthPluginContext, error) {
+	serverRequest := request.Copy()
+	serverRequest[AuthNextOperationKey] = serverOperation
+
+	response, err := conn.RequestAuthentication(serverRequest)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to request auth operation %s: %w", serverOperation, err)
+	}
+
+	if response == nil {
+		response = AuthPluginContext{}
+	}
+	return response, nil
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+)
+
+// GenerateTemporaryPassword returns a temporary password from the string the server gives to hash with
+// the server hashes the same way, the string and the password are concatenated in a zero-padded buffer
+func GenerateTemporaryPassword(stringToHashWith string, password string) string {
+	buf := make([]byte, common.MaxPasswordLength*2)
+	n := copy(buf, []byte(stringToHashWith))
+	copy(buf[n:], []byte(password))
+
+	m := md5.New()
+	m.Write(buf)
+	return hex.EncodeToString(m.Sum(nil))
+}
@@ -190,4 +190,7 @@ const (
 	ATOMIC_APPLY_METADATA_OPERATIONS_APN APINumber = 20002
 	REPLICA_CLOSE_APN                    APINumber = 20004
 	TOUCH_APN                            APINumber = 20007
+	SWITCH_USER_APN                      APINumber = 20013
+
+	AUTHENTICATION_APN APINumber = 110000
 )
@@ -19,3 +19,11 @@ const (
 		MAX_SQL_ROWS               int = 256
 	*/
 )
+
+// server types returned by misc server info
+const (
+	// RCAT_NOT_ENABLED is for servers without the catalog (consumer)
+	RCAT_NOT_ENABLED int = 0
+	// RCAT_ENABLED is for servers having the catalog (provider)
+	RCAT_ENABLED int = 1
+)
@@ -33,4 +33,8 @@ const (
 	STAGE_OBJ_KW          KeyWord = "stage_object"
 	SYNC_OBJ_KW           KeyWord = "sync_object"
 	IN_REPL_KW            KeyWord = "in_repl"
+
+	SWITCH_PROXY_USER_KW           KeyWord = "switch_proxy_user"
+	CLOSE_OPEN_REPLICAS_KW         KeyWord = "close_open_replicas"
+	KEEP_SVR_TO_SVR_CONNECTIONS_KW KeyWord = "keep_svr_to_svr_connections"
 )
@@ -6,7 +6,6 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"encoding/binary"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
@@ -33,7 +32,10 @@ type IRODSConnection struct {
 	account         *types.IRODSAccount
 	requestTimeout  time.Duration
 	tcpBufferSize   int
+	tcpKeepAlive    time.Duration
 	applicationName string
+	endpoint        *types.IRODSEndpoint // overrides host and port of the account if set
+	dialer          Dialer               // dials directly if nil
 
 	connected            bool
 	isSSLSocket          bool
@@ -42,7 +44,10 @@ type IRODSConnection struct {
 	sslSharedSecret      []byte
 	creationTime         time.Time
 	lastSuccessfulAccess time.Time
+	accessMutex          sync.Mutex // guards lastSuccessfulAccess, updated by sender and receiver of async requests
 	clientSignature      string
+	clientUser           string // client user the connection acts for, changed by SwitchUser
+	clientZone           string
 	dirtyTransaction     bool
 	mutex                sync.Mutex
 	locked               bool // true if mutex is locked
@@ -111,12 +116,59 @@ func (conn *IRODSConnection) SetTCPBufferSize(bufferSize int) {
 	conn.tcpBufferSize = bufferSize
 }
 
+// SetTCPKeepAlive sets TCP keepalive period
+// 0 uses the OS default period, negative disables TCP keepalive
+func (conn *IRODSConnection) SetTCPKeepAlive(period time.Duration) {
+	conn.tcpKeepAlive = period
+}
+
+// SetDialer sets the dialer used to connect to the server, e.g., a proxy dialer
+// resource server connections redirected from the connection use the same dialer
+func (conn *IRODSConnection) SetDialer(dialer Dialer) {
+	conn.dialer = dialer
+}
+
+// GetDialer returns the dialer, nil if it dials directly
+func (conn *IRODSConnection) GetDialer() Dialer {
+	return conn.dialer
+}
+
+// SetEndpoint sets the endpoint to connect to, overriding host and port of the account
+// used to connect to one of catalog providers, set before Connect
+func (conn *IRODSConnection) SetEndpoint(endpoint *types.IRODSEndpoint) {
+	conn.endpoint = endpoint
+}
+
+// GetEndpoint returns the endpoint the connection connects to
+func (conn *IRODSConnection) GetEndpoint() *types.IRODSEndpoint {
+	if conn.endpoint != nil {
+		return conn.endpoint
+	}
+
+	return &types.IRODSEndpoint{
+		Host: conn.account.Host,
+		Port: conn.account.Port,
+	}
+}
+
 // SupportParallelUpload checks if the server supports parallel upload
 // available from 4.2.9
 func (conn *IRODSConnection) SupportParallelUpload() bool {
 	return conn.serverVersion.HasHigherVersionThan(4, 2, 9)
 }
 
+// SupportAuthFramework checks if the server supports the authentication framework with auth plugins
+// available from 4.3.0
+func (conn *IRODSConnection) SupportAuthFramework() bool {
+	return conn.serverVersion.HasHigherVersionThan(4, 3, 0)
+}
+
+// SupportSwitchUser checks if the server supports switching client user of the connection
+// available from 4.3.1
+func (conn *IRODSConnection) SupportSwitchUser() bool {
+	return conn.serverVersion.HasHigherVersionThan(4, 3, 1)
+}
+
 func (conn *IRODSConnection) requiresCSNegotiation() bool {
 	return conn.account.ClientServerNegotiation
 }
@@ -148,14 +200,46 @@ func (conn *IRODSConnection) GetCreationTime() time.Time {
 
 // GetLastSuccessfulAccess returns last successful access time
 func (conn *IRODSConnection) GetLastSuccessfulAccess() time.Time {
+	conn.accessMutex.Lock()
+	defer conn.accessMutex.Unlock()
+
 	return conn.lastSuccessfulAccess
 }
 
+// setLastSuccessfulAccess sets last successful access time
+func (conn *IRODSConnection) setLastSuccessfulAccess(t time.Time) {
+	conn.accessMutex.Lock()
+	defer conn.accessMutex.Unlock()
+
+	conn.lastSuccessfulAccess = t
+}
+
 // GetClientSignature returns client signature to be used in password obfuscation
 func (conn *IRODSConnection) GetClientSignature() string {
 	return conn.clientSignature
 }
 
+// SetClientSignature sets client signature to be used in password obfuscation
+func (conn *IRODSConnection) SetClientSignature(signature string) {
+	conn.clientSignature = signature
+}
+
+// GetClientUser returns the client user the connection currently acts for
+func (conn *IRODSConnection) GetClientUser() string {
+	if len(conn.clientUser) == 0 {
+		return conn.account.ClientUser
+	}
+	return conn.clientUser
+}
+
+// GetClientZone returns the zone of the client user the connection currently acts for
+func (conn *IRODSConnection) GetClientZone() string {
+	if len(conn.clientZone) == 0 {
+		return conn.account.ClientZone
+	}
+	return conn.clientZone
+}
+
 // SetTransactionDirty sets if transaction is dirty
 func (conn *IRODSConnection) SetTransactionDirty(dirtyTransaction bool) {
 	conn.dirtyTransaction = dirtyTransaction
@@ -180,7 +264,20 @@ func (conn *IRODSConnection) setSocketOpt(socket net.Conn, bufferSize int) {
 		// nodelay is default
 		//tcpSocket.SetNoDelay(true)
 
-		tcpSocket.SetKeepAlive(true)
+		// TCP keepalive
+		if conn.tcpKeepAlive < 0 {
+			tcpSocket.SetKeepAlive(false)
+		} else {
+			tcpSocket.SetKeepAlive(true)
+
+			if conn.tcpKeepAlive > 0 {
+				sockErr := tcpSocket.SetKeepAlivePeriod(conn.tcpKeepAlive)
+				if sockErr != nil {
+					sockKeepAliveErr := xerrors.Errorf("failed to set tcp keepalive period %v: %w", conn.tcpKeepAlive, sockErr)
+					logger.Errorf("%+v", sockKeepAliveErr)
+				}
+			}
+		}
 
 		// TCP buffer size
 		if bufferSize <= 0 {
@@ -222,17 +319,18 @@ func (conn *IRODSConnection) Connect() error {
 	conn.Lock()
 	defer conn.Unlock()
 
-	server := fmt.Sprintf("%s:%d", conn.account.Host, conn.account.Port)
+	endpoint := conn.GetEndpoint()
+	server := endpoint.GetAddress()
 	logger.Debugf("Connecting to %s", server)
 
 	// must connect to the server in 10 sec
-	var dialer net.Dialer
+	dialer := getDialer(conn.dialer)
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelFunc()
 
-	socket, err := dialer.DialContext(ctx, "tcp", server)
+	socket, err := dialer(ctx, "tcp", server)
 	if err != nil {
-		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", conn.account.Host, conn.account.Port, err.Error(), types.NewConnectionError())
+		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", endpoint.Host, endpoint.Port, err.Error(), types.NewConnectionError())
 		logger.Errorf("%+v", connErr)
 
 		if conn.metrics != nil {
@@ -259,7 +357,7 @@ func (conn *IRODSConnection) Connect() error {
 	}
 
 	if err != nil {
-		connErr := xerrors.Errorf("failed to startup an iRODS connection to server %s and port %d (%s): %w", conn.account.Host, conn.account.Port, err.Error(), types.NewConnectionError())
+		connErr := xerrors.Errorf("failed to startup an iRODS connection to server %s and port %d (%s): %w", endpoint.Host, endpoint.Port, err.Error(), types.NewConnectionError())
 		logger.Errorf("%+v", connErr)
 		_ = conn.disconnectNow()
 		if conn.metrics != nil {
@@ -270,19 +368,24 @@ func (conn *IRODSConnection) Connect() error {
 
 	conn.serverVersion = irodsVersion
 
-	switch conn.account.AuthenticationScheme {
-	case types.AuthSchemeNative:
-		err = conn.loginNative()
-	case types.AuthSchemeGSI:
-		err = conn.loginGSI()
-	case types.AuthSchemePAM:
-		if len(conn.account.PamToken) > 0 {
-			err = conn.loginPAMWithToken()
-		} else {
-			err = conn.loginPAMWithPassword()
+	// use auth plugins if available, otherwise fall back to legacy login, e.g., GSI
+	if conn.SupportAuthFramework() && auth.HasAuthPlugin(auth.GetAuthPluginScheme(conn.account.AuthenticationScheme)) {
+		err = conn.loginWithAuthPlugin()
+	} else {
+		switch conn.account.AuthenticationScheme {
+		case types.AuthSchemeNative:
+			err = conn.loginNative()
+		case types.AuthSchemeGSI:
+			err = conn.loginGSI()
+		case types.AuthSchemePAM:
+			if len(conn.account.PamToken) > 0 {
+				err = conn.loginPAMWithToken()
+			} else {
+				err = conn.loginPAMWithPassword()
+			}
+		default:
+			err = xerrors.Errorf("unknown Authentication Scheme - %s: %w", conn.account.AuthenticationScheme, types.NewConnectionConfigError(conn.account))
 		}
-	default:
-		err = xerrors.Errorf("unknown Authentication Scheme - %s: %w", conn.account.AuthenticationScheme, types.NewConnectionConfigError(conn.account))
 	}
 
 	if err != nil {
@@ -300,8 +403,10 @@ func (conn *IRODSConnection) Connect() error {
 		}
 	}
 
+	conn.clientUser = conn.account.ClientUser
+	conn.clientZone = conn.account.ClientZone
 	conn.connected = true
-	conn.lastSuccessfulAccess = time.Now()
+	conn.setLastSuccessfulAccess(time.Now())
 
 	return nil
 }
@@ -429,21 +534,15 @@ func (conn *IRODSConnection) sslStartup() error {
 		return xerrors.Errorf("SSL Configuration is not set: %w", types.NewConnectionConfigError(conn.account))
 	}
 
-	caCertPool, err := irodsSSLConfig.LoadCACert()
-	if err != nil {
-		return xerrors.Errorf("Failed to load CA Certificates: %w", err)
-	}
-
-	serverName := conn.account.Host
+	serverName := conn.GetEndpoint().Host
 
 	if conn.account.ServerNameTLS != "" {
 		serverName = conn.account.ServerNameTLS
 	}
 
-	sslConf := &tls.Config{
-		RootCAs:            caCertPool,
-		ServerName:         serverName,
-		InsecureSkipVerify: conn.account.SkipVerifyTLS,
+	sslConf, err := irodsSSLConfig.GetTLSConfig(serverName, conn.account.SkipVerifyTLS)
+	if err != nil {
+		return xerrors.Errorf("failed to get TLS configuration (%s): %w", err.Error(), types.NewConnectionConfigError(conn.account))
 	}
 
 	// Create a side connection using the existing socket
@@ -584,6 +683,41 @@ func (conn *IRODSConnection) loginPAMWithToken() error {
 	return conn.login(conn.account.PamToken)
 }
 
+// loginWithAuthPlugin logs in using the auth plugin of the authentication scheme, available from 4.3.0
+func (conn *IRODSConnection) loginWithAuthPlugin() error {
+	logger := log.WithFields(log.Fields{
+		"package":  "connection",
+		"struct":   "IRODSConnection",
+		"function": "loginWithAuthPlugin",
+	})
+
+	scheme := auth.GetAuthPluginScheme(conn.account.AuthenticationScheme)
+	plugin, err := auth.GetAuthPlugin(scheme)
+	if err != nil {
+		return xerrors.Errorf("unknown Authentication Scheme - %s (%s): %w", conn.account.AuthenticationScheme, err.Error(), types.NewConnectionConfigError(conn.account))
+	}
+
+	logger.Debugf("Logging in using auth plugin %s", scheme)
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	if err != nil {
+		return xerrors.Errorf("received irods authentication error (%s): %w", err.Error(), types.NewAuthError(conn.account))
+	}
+	return nil
+}
+
+// RequestAuthentication sends a request to the server-side auth plugin, used by auth plugins
+func (conn *IRODSConnection) RequestAuthentication(request auth.AuthPluginContext) (auth.AuthPluginContext, error) {
+	authRequest := message.NewIRODSMessageAuthenticationRequest(request)
+	authResponse := message.IRODSMessageAuthenticationResponse{}
+	err := conn.RequestAndCheck(authRequest, &authResponse, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to receive authentication response: %w", err)
+	}
+
+	return auth.AuthPluginContext(authResponse.Context), nil
+}
+
 // Disconnect disconnects
 func (conn *IRODSConnection) disconnectNow() error {
 	conn.connected = false
@@ -621,7 +755,7 @@ func (conn *IRODSConnection) Disconnect() error {
 	disconnect := message.NewIRODSMessageDisconnect()
 	err := conn.RequestWithoutResponse(disconnect)
 
-	conn.lastSuccessfulAccess = time.Now()
+	conn.setLastSuccessfulAccess(time.Now())
 
 	err2 := conn.disconnectNow()
 	if err2 != nil {
@@ -674,7 +808,7 @@ func (conn *IRODSConnection) SendWithTrackerCallBack(buffer []byte, size int, ca
 		}
 	}
 
-	conn.lastSuccessfulAccess = time.Now()
+	conn.setLastSuccessfulAccess(time.Now())
 
 	return nil
 }
@@ -711,7 +845,7 @@ func (conn *IRODSConnection) SendFromReader(src io.Reader, size int64) error {
 		}
 	}
 
-	conn.lastSuccessfulAccess = time.Now()
+	conn.setLastSuccessfulAccess(time.Now())
 
 	return nil
 }
@@ -747,7 +881,7 @@ func (conn *IRODSConnection) RecvWithTrackerCallBack(buffer []byte, size int, ca
 		}
 	}
 
-	conn.lastSuccessfulAccess = time.Now()
+	conn.setLastSuccessfulAccess(time.Now())
 
 	return readLen, nil
 }
@@ -780,7 +914,7 @@ func (conn *IRODSConnection) RecvToWriter(writer io.Writer, size int64) (int64,
 		}
 	}
 
-	conn.lastSuccessfulAccess = time.Now()
+	conn.setLastSuccessfulAccess(time.Now())
 
 	return copyLen, nil
 }
@@ -997,11 +1131,84 @@ func (conn *IRODSConnection) PoorMansRollback() error {
 		return xerrors.Errorf("connection must be locked before use")
 	}
 
-	dummyCol := fmt.Sprintf("/%s/home/%s", conn.account.ClientZone, conn.account.ClientUser)
+	dummyCol := fmt.Sprintf("/%s/home/%s", conn.GetClientZone(), conn.GetClientUser())
 
 	return conn.poorMansEndTransaction(dummyCol, false)
 }
 
+// Ping checks if the connection is alive by requesting misc server info, a lightweight API
+// it does not mark the transaction dirty or refresh the last successful access time, so idle connections still expire
+func (conn *IRODSConnection) Ping() error {
+	if !conn.locked {
+		return xerrors.Errorf("connection must be locked before use")
+	}
+
+	if !conn.connected {
+		return xerrors.Errorf("connection is not established")
+	}
+
+	dirtyTransaction := conn.dirtyTransaction
+	lastSuccessfulAccess := conn.GetLastSuccessfulAccess()
+
+	defer func() {
+		conn.dirtyTransaction = dirtyTransaction
+		conn.setLastSuccessfulAccess(lastSuccessfulAccess)
+	}()
+
+	request := message.NewIRODSMessageGetMiscServerInfoRequest()
+	response := message.IRODSMessageGetMiscServerInfoResponse{}
+	err := conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to ping: %w", err)
+	}
+
+	return nil
+}
+
+// PingWithTimeout checks the connection is alive, a request and its response must be done in the timeout
+// the timeout is used instead of the request timeout of the connection, so a dead connection is detected quickly
+func (conn *IRODSConnection) PingWithTimeout(timeout time.Duration) error {
+	if !conn.locked {
+		return xerrors.Errorf("connection must be locked before use")
+	}
+
+	requestTimeout := conn.requestTimeout
+	conn.requestTimeout = timeout
+
+	defer func() {
+		conn.requestTimeout = requestTimeout
+	}()
+
+	return conn.Ping()
+}
+
+// SwitchUser switches the client user of the connection, the proxy user is not changed
+// the proxy user must be a rodsadmin, available from 4.3.1
+func (conn *IRODSConnection) SwitchUser(clientUser string, clientZone string) error {
+	if !conn.locked {
+		return xerrors.Errorf("connection must be locked before use")
+	}
+
+	if !conn.connected {
+		return xerrors.Errorf("connection is not established")
+	}
+
+	if !conn.SupportSwitchUser() {
+		return xerrors.Errorf("does not support switching user in current iRODS Version")
+	}
+
+	request := message.NewIRODSMessageSwitchUserRequest(clientUser, clientZone)
+	response := message.IRODSMessageSwitchUserResponse{}
+	err := conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to switch user to %s#%s: %w", clientUser, clientZone, err)
+	}
+
+	conn.clientUser = clientUser
+	conn.clientZone = clientZone
+	return nil
+}
+
 func (conn *IRODSConnection) endTransaction(commit bool) error {
 	request := message.NewIRODSMessageEndTransactionRequest(commit)
 	response := message.IRODSMessageEndTransactionResponse{}
@@ -1050,10 +1257,5 @@ func (conn *IRODSConnection) GetMetrics() *metrics.IRODSMetrics {
 
 // createClientSignature creates a client signature from auth challenge
 func (conn *IRODSConnection) createClientSignature(challenge []byte) string {
-	if len(challenge) > 16 {
-		challenge = challenge[:16]
-	}
-
-	signature := hex.EncodeToString(challenge)
-	return signature
+	return auth.GenerateClientSignature(challenge)
 }
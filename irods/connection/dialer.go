@@ -0,0 +1,158 @@
+package connection
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Dialer dials a network connection, compatible with net.Dialer.DialContext
+type Dialer func(ctx context.Context, network string, address string) (net.Conn, error)
+
+// dialDirect dials without proxies
+func dialDirect(ctx context.Context, network string, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
+
+// getDialer returns the dialer, or the dialer dialing directly if nil
+func getDialer(dialer Dialer) Dialer {
+	if dialer == nil {
+		return dialDirect
+	}
+	return dialer
+}
+
+// getEnvAny returns the value of the first environment variable set
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if val := os.Getenv(name); len(val) > 0 {
+			return val
+		}
+	}
+	return ""
+}
+
+// parseProxyURL parses proxy url, http is used if scheme is not given
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to parse proxy url %s: %w", proxy, err)
+	}
+
+	if len(proxyURL.Hostname()) == 0 {
+		return nil, xerrors.Errorf("empty host in proxy url %s", proxy)
+	}
+	return proxyURL, nil
+}
+
+// getProxyAddress returns host:port of the proxy, the default port of the scheme is used if port is not given
+func getProxyAddress(proxyURL *url.URL, defaultPort string) string {
+	port := proxyURL.Port()
+	if len(port) == 0 {
+		port = defaultPort
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// NewProxyDialer creates a dialer connecting via the proxy
+// supports socks5, socks5h (the proxy resolves host names), http and https (HTTP CONNECT) schemes
+// forward dials the proxy, the proxy is dialed directly if nil
+func NewProxyDialer(proxyURL *url.URL, forward Dialer) (Dialer, error) {
+	switch strings.ToLower(proxyURL.Scheme) {
+	case "socks5", "socks5h":
+		return NewSOCKS5Dialer(proxyURL, forward), nil
+	case "http", "https":
+		return NewHTTPConnectDialer(proxyURL, forward), nil
+	default:
+		return nil, xerrors.Errorf("unknown proxy scheme %s", proxyURL.Scheme)
+	}
+}
+
+// NewDialerFromEnvironment creates a dialer using the proxy set in ALL_PROXY or HTTPS_PROXY environment variables
+// ALL_PROXY takes precedence, hosts matching NO_PROXY are dialed directly
+// returns nil if no proxy is set
+func NewDialerFromEnvironment() (Dialer, error) {
+	proxy := getEnvAny("ALL_PROXY", "all_proxy")
+	if len(proxy) == 0 {
+		proxy = getEnvAny("HTTPS_PROXY", "https_proxy")
+	}
+
+	if len(proxy) == 0 {
+		return nil, nil
+	}
+
+	proxyURL, err := parseProxyURL(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyDialer, err := NewProxyDialer(proxyURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	noProxy := getEnvAny("NO_PROXY", "no_proxy")
+	if len(noProxy) == 0 {
+		return proxyDialer, nil
+	}
+
+	noProxyPatterns := strings.Split(noProxy, ",")
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+
+		if matchNoProxy(host, noProxyPatterns) {
+			return dialDirect(ctx, network, address)
+		}
+		return proxyDialer(ctx, network, address)
+	}, nil
+}
+
+// matchNoProxy returns true if the host matches one of NO_PROXY patterns
+// a pattern can be "*", a domain name matching its subdomains, an IP address or a CIDR
+func matchNoProxy(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	hostIP := net.ParseIP(host)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if len(pattern) == 0 {
+			continue
+		}
+
+		if pattern == "*" {
+			return true
+		}
+
+		if hostIP != nil {
+			if _, ipNet, err := net.ParseCIDR(pattern); err == nil {
+				if ipNet.Contains(hostIP) {
+					return true
+				}
+				continue
+			}
+
+			if patternIP := net.ParseIP(pattern); patternIP != nil && patternIP.Equal(hostIP) {
+				return true
+			}
+			continue
+		}
+
+		pattern = strings.TrimPrefix(pattern, ".")
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
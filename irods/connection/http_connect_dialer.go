@@ -0,0 +1,127 @@
+package connection
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	httpProxyPortDefault  string = "80"
+	httpsProxyPortDefault string = "443"
+)
+
+// bufferedConn is a connection having bytes read ahead in the reader
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read reads bytes read ahead first
+func (conn *bufferedConn) Read(buffer []byte) (int, error) {
+	return conn.reader.Read(buffer)
+}
+
+// NewHTTPConnectDialer creates a dialer connecting via the HTTP proxy using CONNECT method
+// the proxy is connected with TLS if scheme is https
+// forward dials the proxy, the proxy is dialed directly if nil
+func NewHTTPConnectDialer(proxyURL *url.URL, forward Dialer) Dialer {
+	useTLS := strings.ToLower(proxyURL.Scheme) == "https"
+
+	defaultPort := httpProxyPortDefault
+	if useTLS {
+		defaultPort = httpsProxyPortDefault
+	}
+
+	proxyAddress := getProxyAddress(proxyURL, defaultPort)
+
+	proxyAuthorization := ""
+	if proxyURL.User != nil {
+		username := proxyURL.User.Username()
+		password, _ := proxyURL.User.Password()
+		proxyAuthorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	forward = getDialer(forward)
+
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		proxyConn, err := forward(ctx, network, proxyAddress)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to connect to http proxy %s: %w", proxyAddress, err)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			proxyConn.SetDeadline(deadline)
+		}
+
+		if useTLS {
+			tlsConn := tls.Client(proxyConn, &tls.Config{
+				ServerName: proxyURL.Hostname(),
+			})
+
+			err = tlsConn.HandshakeContext(ctx)
+			if err != nil {
+				proxyConn.Close()
+				return nil, xerrors.Errorf("failed to establish tls to http proxy %s: %w", proxyAddress, err)
+			}
+
+			proxyConn = tlsConn
+		}
+
+		conn, err := httpConnect(proxyConn, address, proxyAuthorization)
+		if err != nil {
+			proxyConn.Close()
+			return nil, xerrors.Errorf("failed to connect to %s via http proxy %s: %w", address, proxyAddress, err)
+		}
+
+		conn.SetDeadline(time.Time{})
+		return conn, nil
+	}
+}
+
+// httpConnect requests CONNECT to the address
+func httpConnect(conn net.Conn, address string, proxyAuthorization string) (net.Conn, error) {
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+
+	if len(proxyAuthorization) > 0 {
+		request.Header.Set("Proxy-Authorization", proxyAuthorization)
+	}
+
+	err := request.Write(conn)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to send connect request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to receive connect response: %w", err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("failed to connect, proxy returned %s", response.Status)
+	}
+
+	if reader.Buffered() > 0 {
+		// the server sent bytes with the response
+		return &bufferedConn{
+			Conn:   conn,
+			reader: reader,
+		}, nil
+	}
+	return conn, nil
+}
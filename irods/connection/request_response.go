@@ -1,6 +1,8 @@
 package connection
 
 import (
+	"sync"
+
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/message"
 	"golang.org/x/xerrors"
@@ -86,7 +88,23 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 	waitResponseChan := make(chan RequestResponsePair, 100)
 	outputPair := make(chan RequestResponsePair, 100)
 
+	// lastErr is shared by sender and receiver
 	var lastErr error
+	lastErrMutex := sync.Mutex{}
+
+	getLastErr := func() error {
+		lastErrMutex.Lock()
+		defer lastErrMutex.Unlock()
+
+		return lastErr
+	}
+
+	setLastErr := func(err error) {
+		lastErrMutex.Lock()
+		defer lastErrMutex.Unlock()
+
+		lastErr = err
+	}
 
 	// sender
 	go func() {
@@ -99,8 +117,8 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 			}
 
 			// if errored before? skip
-			if lastErr != nil {
-				pair.Error = lastErr
+			if err := getLastErr(); err != nil {
+				pair.Error = err
 				waitResponseChan <- pair
 				continue
 			}
@@ -111,8 +129,8 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 					conn.metrics.IncreaseCounterForRequestResponseFailures(1)
 				}
 
-				lastErr = err
-				pair.Error = lastErr
+				pair.Error = err
+				setLastErr(pair.Error)
 				waitResponseChan <- pair
 				continue
 			}
@@ -123,8 +141,8 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 					conn.metrics.IncreaseCounterForRequestResponseFailures(1)
 				}
 
-				lastErr = xerrors.Errorf("failed to send a request message: %w", err)
-				pair.Error = lastErr
+				pair.Error = xerrors.Errorf("failed to send a request message: %w", err)
+				setLastErr(pair.Error)
 				waitResponseChan <- pair
 				continue
 			}
@@ -144,9 +162,9 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 			}
 
 			// if errored before? skip
-			if lastErr != nil {
+			if err := getLastErr(); err != nil {
 				if pair.Error == nil {
-					pair.Error = lastErr
+					pair.Error = err
 				}
 				outputPair <- pair
 				continue
@@ -160,8 +178,8 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 					conn.metrics.IncreaseCounterForRequestResponseFailures(1)
 				}
 
-				lastErr = xerrors.Errorf("failed to receive a response message: %w", err)
-				pair.Error = lastErr
+				pair.Error = xerrors.Errorf("failed to receive a response message: %w", err)
+				setLastErr(pair.Error)
 				outputPair <- pair
 				continue
 			}
@@ -172,8 +190,8 @@ func (conn *IRODSConnection) RequestAsyncWithTrackerCallBack(rrChan chan Request
 					conn.metrics.IncreaseCounterForRequestResponseFailures(1)
 				}
 
-				lastErr = xerrors.Errorf("failed to parse response message: %w", err)
-				pair.Error = lastErr
+				pair.Error = xerrors.Errorf("failed to parse response message: %w", err)
+				setLastErr(pair.Error)
 				outputPair <- pair
 				continue
 			}
@@ -162,11 +162,12 @@ func (conn *IRODSResourceServerConnection) Connect() error {
 	logger.Debugf("Connecting to %s", server)
 
 	// must connect to the server in 10 sec
-	var dialer net.Dialer
+	// uses the dialer of the control connection, so redirected connections go through the same proxy
+	dialer := getDialer(conn.controlConnection.GetDialer())
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelFunc()
 
-	socket, err := dialer.DialContext(ctx, "tcp", server)
+	socket, err := dialer(ctx, "tcp", server)
 	if err != nil {
 		connErr := xerrors.Errorf("failed to connect to specified host %s and port %d (%s): %w", conn.serverInfo.Host, conn.serverInfo.Port, err.Error(), types.NewConnectionError())
 		logger.Errorf("%+v", connErr)
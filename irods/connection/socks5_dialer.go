@@ -0,0 +1,231 @@
+package connection
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// SOCKS5 protocol, RFC 1928 and RFC 1929
+const (
+	socks5Version              byte = 0x05
+	socks5AuthNone             byte = 0x00
+	socks5AuthUsernamePassword byte = 0x02
+	socks5AuthNoAcceptable     byte = 0xff
+	socks5CommandConnect       byte = 0x01
+	socks5AddressIPv4          byte = 0x01
+	socks5AddressDomain        byte = 0x03
+	socks5AddressIPv6          byte = 0x04
+	socks5AuthVersion          byte = 0x01
+
+	socks5ProxyPortDefault string = "1080"
+)
+
+var socks5ReplyMessages = map[byte]string{
+	0x01: "general SOCKS server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// NewSOCKS5Dialer creates a dialer connecting via the SOCKS5 proxy
+// host names are resolved locally with socks5 scheme, and by the proxy with socks5h scheme
+// forward dials the proxy, the proxy is dialed directly if nil
+func NewSOCKS5Dialer(proxyURL *url.URL, forward Dialer) Dialer {
+	proxyAddress := getProxyAddress(proxyURL, socks5ProxyPortDefault)
+	resolveLocally := strings.ToLower(proxyURL.Scheme) != "socks5h"
+
+	username := ""
+	password := ""
+	if proxyURL.User != nil {
+		username = proxyURL.User.Username()
+		password, _ = proxyURL.User.Password()
+	}
+
+	forward = getDialer(forward)
+
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, portString, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to split host and port of %s: %w", address, err)
+		}
+
+		port, err := strconv.Atoi(portString)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse port %s: %w", portString, err)
+		}
+
+		if resolveLocally && net.ParseIP(host) == nil {
+			addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to resolve host %s: %w", host, err)
+			}
+
+			if len(addrs) == 0 {
+				return nil, xerrors.Errorf("failed to resolve host %s", host)
+			}
+			host = addrs[0].IP.String()
+		}
+
+		proxyConn, err := forward(ctx, network, proxyAddress)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to connect to socks5 proxy %s: %w", proxyAddress, err)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok {
+			proxyConn.SetDeadline(deadline)
+		}
+
+		err = socks5Handshake(proxyConn, host, port, username, password)
+		if err != nil {
+			proxyConn.Close()
+			return nil, xerrors.Errorf("failed to connect to %s via socks5 proxy %s: %w", address, proxyAddress, err)
+		}
+
+		proxyConn.SetDeadline(time.Time{})
+		return proxyConn, nil
+	}
+}
+
+// socks5Handshake negotiates auth and requests CONNECT to host and port
+func socks5Handshake(conn net.Conn, host string, port int, username string, password string) error {
+	// negotiate auth method
+	methods := []byte{socks5AuthNone}
+	if len(username) > 0 {
+		methods = append(methods, socks5AuthUsernamePassword)
+	}
+
+	greeting := []byte{socks5Version, byte(len(methods))}
+	greeting = append(greeting, methods...)
+
+	_, err := conn.Write(greeting)
+	if err != nil {
+		return xerrors.Errorf("failed to send greeting: %w", err)
+	}
+
+	reply := make([]byte, 2)
+	_, err = io.ReadFull(conn, reply)
+	if err != nil {
+		return xerrors.Errorf("failed to receive auth method: %w", err)
+	}
+
+	if reply[0] != socks5Version {
+		return xerrors.Errorf("unexpected socks version %d", reply[0])
+	}
+
+	switch reply[1] {
+	case socks5AuthNone:
+		// no auth
+	case socks5AuthUsernamePassword:
+		err = socks5Authenticate(conn, username, password)
+		if err != nil {
+			return err
+		}
+	case socks5AuthNoAcceptable:
+		return xerrors.Errorf("no acceptable auth methods")
+	default:
+		return xerrors.Errorf("unsupported auth method %d", reply[1])
+	}
+
+	// connect
+	request := []byte{socks5Version, socks5CommandConnect, 0x00}
+	if ip := net.ParseIP(host); ip != nil {
+		if ipv4 := ip.To4(); ipv4 != nil {
+			request = append(request, socks5AddressIPv4)
+			request = append(request, ipv4...)
+		} else {
+			request = append(request, socks5AddressIPv6)
+			request = append(request, ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return xerrors.Errorf("too long host name %s", host)
+		}
+
+		request = append(request, socks5AddressDomain, byte(len(host)))
+		request = append(request, []byte(host)...)
+	}
+
+	request = append(request, byte(port>>8), byte(port))
+
+	_, err = conn.Write(request)
+	if err != nil {
+		return xerrors.Errorf("failed to send connect request: %w", err)
+	}
+
+	// VER, REP, RSV, ATYP
+	header := make([]byte, 4)
+	_, err = io.ReadFull(conn, header)
+	if err != nil {
+		return xerrors.Errorf("failed to receive connect reply: %w", err)
+	}
+
+	if header[1] != 0x00 {
+		if message, ok := socks5ReplyMessages[header[1]]; ok {
+			return xerrors.Errorf("failed to connect, %s", message)
+		}
+		return xerrors.Errorf("failed to connect, reply code %d", header[1])
+	}
+
+	// skip bound address and port
+	boundAddressLen := 0
+	switch header[3] {
+	case socks5AddressIPv4:
+		boundAddressLen = net.IPv4len
+	case socks5AddressIPv6:
+		boundAddressLen = net.IPv6len
+	case socks5AddressDomain:
+		domainLen := make([]byte, 1)
+		_, err = io.ReadFull(conn, domainLen)
+		if err != nil {
+			return xerrors.Errorf("failed to receive bound address: %w", err)
+		}
+		boundAddressLen = int(domainLen[0])
+	default:
+		return xerrors.Errorf("unknown bound address type %d", header[3])
+	}
+
+	_, err = io.ReadFull(conn, make([]byte, boundAddressLen+2))
+	if err != nil {
+		return xerrors.Errorf("failed to receive bound address: %w", err)
+	}
+	return nil
+}
+
+// socks5Authenticate authenticates with username and password
+func socks5Authenticate(conn net.Conn, username string, password string) error {
+	if len(username) > 255 || len(password) > 255 {
+		return xerrors.Errorf("too long username or password")
+	}
+
+	request := []byte{socks5AuthVersion, byte(len(username))}
+	request = append(request, []byte(username)...)
+	request = append(request, byte(len(password)))
+	request = append(request, []byte(password)...)
+
+	_, err := conn.Write(request)
+	if err != nil {
+		return xerrors.Errorf("failed to send username and password: %w", err)
+	}
+
+	reply := make([]byte, 2)
+	_, err = io.ReadFull(conn, reply)
+	if err != nil {
+		return xerrors.Errorf("failed to receive auth result: %w", err)
+	}
+
+	if reply[1] != 0x00 {
+		return xerrors.Errorf("failed to authenticate with username and password")
+	}
+	return nil
+}
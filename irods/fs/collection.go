@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
@@ -592,6 +593,79 @@ func ListSubCollections(conn *connection.IRODSConnection, path string) ([]*types
 	return collections, nil
 }
 
+// ListSubCollectionsRecursively lists all collections under the given collection in a single query
+// the given collection itself is not included
+func ListSubCollectionsRecursively(conn *connection.IRODSConnection, path string) ([]*types.IRODSCollection, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForList(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	collections, err := queryCollections(conn, func(query *message.IRODSMessageQueryRequest) {
+		condVal := fmt.Sprintf("like '%s'", util.MakeIRODSSubPathLikePattern(path))
+		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, condVal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the pattern may match more
+	subCollections := []*types.IRODSCollection{}
+	for _, collection := range collections {
+		if util.IsIRODSSubPath(path, collection.Path) {
+			subCollections = append(subCollections, collection)
+		}
+	}
+
+	return subCollections, nil
+}
+
+// ListCollectionsModifiedSince lists the given collection and all collections under it modified after the given time
+// iRODS keeps modification time in seconds, so collections modified in the same second as the given time are included
+func ListCollectionsModifiedSince(conn *connection.IRODSConnection, path string, since time.Time) ([]*types.IRODSCollection, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForList(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	collPath := strings.TrimSuffix(path, "/")
+
+	collections, err := queryCollections(conn, func(query *message.IRODSMessageQueryRequest) {
+		condVal := fmt.Sprintf("like '%s' || like '%s'", util.MakeIRODSLikePattern(collPath), util.MakeIRODSSubPathLikePattern(collPath))
+		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, condVal)
+		query.AddCondition(common.ICAT_COLUMN_COLL_MODIFY_TIME, fmt.Sprintf(">= '%s'", util.GetIRODSDateTimeString(since)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the pattern may match more
+	matchedCollections := []*types.IRODSCollection{}
+	for _, collection := range collections {
+		if collection.Path == collPath || util.IsIRODSSubPath(collPath, collection.Path) {
+			matchedCollections = append(matchedCollections, collection)
+		}
+	}
+
+	return matchedCollections, nil
+}
+
 // CreateCollection creates a collection for the path
 func CreateCollection(conn *connection.IRODSConnection, path string, recurse bool) error {
 	if conn == nil || !conn.IsConnected() {
@@ -832,6 +832,90 @@ func ListDataObjectsMasterReplica(conn *connection.IRODSConnection, collection *
 	return mergedDataObjects, nil
 }
 
+// ListDataObjectsMasterReplicaRecursively lists all data objects under sub-collections of the given collection in a single query, returns only master replica
+// data objects directly in the given collection are not included, use ListDataObjectsMasterReplica for them
+func ListDataObjectsMasterReplicaRecursively(conn *connection.IRODSConnection, path string) ([]*types.IRODSDataObject, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForList(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	dataObjects, err := queryDataObjectReplicas(conn, func(query *message.IRODSMessageQueryRequest) {
+		collCondVal := fmt.Sprintf("like '%s'", util.MakeIRODSSubPathLikePattern(path))
+		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
+		query.AddCondition(common.ICAT_COLUMN_D_REPL_STATUS, "= '1'")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the pattern may match more
+	subDataObjects := []*types.IRODSDataObject{}
+	for _, object := range dataObjects {
+		if util.IsIRODSSubPath(path, util.GetIRODSPathDirname(object.Path)) {
+			subDataObjects = append(subDataObjects, object)
+		}
+	}
+
+	// merge data objects per file
+	return mergeDataObjectReplicas(subDataObjects, func(kept *types.IRODSReplica, replica *types.IRODSReplica) bool {
+		// found old replica (meaning master) - replace
+		return kept.CreateTime.After(replica.CreateTime)
+	}), nil
+}
+
+// ListDataObjectsModifiedSince lists all data objects in the given collection and collections under it modified after the given time
+// returns only the most recently modified replica of each data object
+// iRODS keeps modification time in seconds, so data objects modified in the same second as the given time are included
+func ListDataObjectsModifiedSince(conn *connection.IRODSConnection, path string, since time.Time) ([]*types.IRODSDataObject, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForList(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	collPath := strings.TrimSuffix(path, "/")
+
+	dataObjects, err := queryDataObjectReplicas(conn, func(query *message.IRODSMessageQueryRequest) {
+		collCondVal := fmt.Sprintf("like '%s' || like '%s'", util.MakeIRODSLikePattern(collPath), util.MakeIRODSSubPathLikePattern(collPath))
+		query.AddCondition(common.ICAT_COLUMN_COLL_NAME, collCondVal)
+		query.AddCondition(common.ICAT_COLUMN_D_MODIFY_TIME, fmt.Sprintf(">= '%s'", util.GetIRODSDateTimeString(since)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the pattern may match more
+	matchedDataObjects := []*types.IRODSDataObject{}
+	for _, object := range dataObjects {
+		objectCollPath := util.GetIRODSPathDirname(object.Path)
+		if objectCollPath == collPath || util.IsIRODSSubPath(collPath, objectCollPath) {
+			matchedDataObjects = append(matchedDataObjects, object)
+		}
+	}
+
+	// merge data objects per file
+	return mergeDataObjectReplicas(matchedDataObjects, func(kept *types.IRODSReplica, replica *types.IRODSReplica) bool {
+		// found recently modified replica - replace
+		return replica.ModifyTime.After(kept.ModifyTime)
+	}), nil
+}
+
 // ListDataObjectMeta returns a data object metadata for the path
 func ListDataObjectMeta(conn *connection.IRODSConnection, collection *types.IRODSCollection, filename string) ([]*types.IRODSMeta, error) {
 	if conn == nil || !conn.IsConnected() {
@@ -1650,6 +1734,107 @@ func GetReplicaAccessInfo(conn *connection.IRODSConnection, handle *types.IRODSF
 	return response.ReplicaToken, resourceHierarchy, nil
 }
 
+// GetFileDescriptorInfo returns information of the replica opened with the file handle
+// DataSize is the catalog value at open time, writes via the file handle are reported only in BytesWritten
+func GetFileDescriptorInfo(conn *connection.IRODSConnection, handle *types.IRODSFileHandle) (*types.IRODSFileDescriptorInfo, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	metrics := conn.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForStat(1)
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	request := message.NewIRODSMessageGetDescriptorInfoRequest(handle.FileDescriptor)
+	response := message.IRODSMessageGetDescriptorInfoResponse{}
+	err := conn.RequestAndCheck(request, &response, nil)
+	if err != nil {
+		if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+			return nil, xerrors.Errorf("failed to find the data object for path %s: %w", handle.Path, types.NewFileNotFoundError(handle.Path))
+		}
+		return nil, xerrors.Errorf("failed to get file descriptor info: %w", err)
+	}
+
+	// checksum recorded in the catalog, or the one given when the file is opened
+	checksumString := getDescriptorInfoString(response.DataObjectInfo, "checksum")
+	if len(checksumString) == 0 {
+		checksumString = response.Checksum
+	}
+
+	checksum, err := types.CreateIRODSChecksum(checksumString)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create iRODS checksum: %w", err)
+	}
+
+	dataSize := getDescriptorInfoInt64(response.DataObjectInfo, "data_size", response.DataSize)
+	path := getDescriptorInfoString(response.DataObjectInfo, "object_path")
+	if len(path) == 0 {
+		path = handle.Path
+	}
+
+	return &types.IRODSFileDescriptorInfo{
+		FileDescriptor:    handle.FileDescriptor,
+		Path:              path,
+		DataID:            getDescriptorInfoInt64(response.DataObjectInfo, "data_id", 0),
+		ReplicaNumber:     getDescriptorInfoInt64(response.DataObjectInfo, "replica_number", 0),
+		ResourceName:      getDescriptorInfoString(response.DataObjectInfo, "resource_name"),
+		ResourceHierarchy: getDescriptorInfoString(response.DataObjectInfo, "resource_hierarchy"),
+		PhysicalPath:      getDescriptorInfoString(response.DataObjectInfo, "file_path"),
+		DataSize:          dataSize,
+		BytesWritten:      response.BytesWritten,
+		Checksum:          checksum,
+		ReplicaStatus:     int(getDescriptorInfoInt64(response.DataObjectInfo, "replica_status", int64(response.ReplicaStatus))),
+		OpenFlags:         int(getDescriptorInfoInt64(response.DataObjectInput, "open_flags", 0)),
+		OperationType:     response.OperationType,
+		ReplicaToken:      response.ReplicaToken,
+	}, nil
+}
+
+// getDescriptorInfoString returns a string value of the key in descriptor info, returns empty string if not exist
+func getDescriptorInfoString(info map[string]interface{}, key string) string {
+	if info == nil {
+		return ""
+	}
+
+	value, ok := info[key]
+	if !ok || value == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%v", value))
+}
+
+// getDescriptorInfoInt64 returns an integer value of the key in descriptor info, returns defaultValue if not exist
+// numbers may be encoded as strings
+func getDescriptorInfoInt64(info map[string]interface{}, key string, defaultValue int64) int64 {
+	if info == nil {
+		return defaultValue
+	}
+
+	value, ok := info[key]
+	if !ok || value == nil {
+		return defaultValue
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		intValue, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return defaultValue
+		}
+		return intValue
+	default:
+		return defaultValue
+	}
+}
+
 // SeekDataObject moves file pointer of a data object, returns offset
 func SeekDataObject(conn *connection.IRODSConnection, handle *types.IRODSFileHandle, offset int64, whence types.Whence) (int64, error) {
 	if conn == nil || !conn.IsConnected() {
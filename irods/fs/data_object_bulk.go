@@ -45,6 +45,12 @@ func CloseDataObjectReplica(conn *connection.IRODSConnection, handle *types.IROD
 	return nil
 }
 
+// acquireTransferConnection returns a connection for data transfer
+// it is acquired with low priority, so short operations are served first when all connections are occupied
+func acquireTransferConnection(sess *session.IRODSSession) (*connection.IRODSConnection, error) {
+	return sess.AcquireConnectionWithPriority(session.ConnectionPriorityLow)
+}
+
 // UploadDataObjectFromBuffer put a data object to the iRODS path from buffer
 func UploadDataObjectFromBuffer(session *session.IRODSSession, buffer bytes.Buffer, irodsPath string, resource string, replicate bool, callback common.TrackerCallBack) error {
 	// use default resource when resource param is empty
@@ -55,7 +61,7 @@ func UploadDataObjectFromBuffer(session *session.IRODSSession, buffer bytes.Buff
 
 	fileLength := int64(buffer.Len())
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
@@ -121,7 +127,7 @@ func UploadDataObject(session *session.IRODSSession, localPath string, irodsPath
 
 	logger.Debugf("upload data object %s", localPath)
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
@@ -410,7 +416,7 @@ func DownloadDataObjectToBuffer(session *session.IRODSSession, irodsPath string,
 		resource = account.DefaultResource
 	}
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
@@ -487,7 +493,7 @@ func DownloadDataObject(session *session.IRODSSession, irodsPath string, resourc
 		resource = account.DefaultResource
 	}
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
@@ -587,7 +593,7 @@ func DownloadDataObjectResumable(session *session.IRODSSession, irodsPath string
 		return xerrors.Errorf("failed to write transfer status file header for %s: %w", localPath, err)
 	}
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		transferStatusLocal.CloseStatusFile()
 		return xerrors.Errorf("failed to get connection: %w", err)
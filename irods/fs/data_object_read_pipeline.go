@@ -0,0 +1,192 @@
+package fs
+
+import (
+	"io"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// DataObjectReadPipeline reads a data object sequentially, keeping read requests for following blocks outstanding
+// the connection is locked until the pipeline is closed, no other requests can be made on the connection
+type DataObjectReadPipeline struct {
+	connection   *connection.IRODSConnection
+	handle       *types.IRODSFileHandle
+	requestChan  chan connection.RequestResponsePair
+	responseChan chan connection.RequestResponsePair
+	outstanding  int
+	lastBuffer   []byte // buffer of the block returned last, reused for a next request
+	serverOffset int64
+	eof          bool
+	lastErr      error
+	closed       bool
+}
+
+// NewDataObjectReadPipeline creates a new DataObjectReadPipeline and sends read requests for window blocks of blockSize
+// offset is the current offset of the server-side file descriptor
+func NewDataObjectReadPipeline(conn *connection.IRODSConnection, handle *types.IRODSFileHandle, offset int64, blockSize int, window int) (*DataObjectReadPipeline, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	if blockSize <= 0 || window <= 0 {
+		return nil, xerrors.Errorf("invalid block size %d or window %d", blockSize, window)
+	}
+
+	// lock the connection, unlocked on close
+	conn.Lock()
+
+	requestChan := make(chan connection.RequestResponsePair, window)
+
+	pipeline := &DataObjectReadPipeline{
+		connection:   conn,
+		handle:       handle,
+		requestChan:  requestChan,
+		responseChan: conn.RequestAsyncWithTrackerCallBack(requestChan),
+		outstanding:  0,
+		lastBuffer:   nil,
+		serverOffset: offset,
+		eof:          false,
+		lastErr:      nil,
+		closed:       false,
+	}
+
+	for i := 0; i < window; i++ {
+		pipeline.request(make([]byte, blockSize))
+	}
+
+	return pipeline, nil
+}
+
+// request sends a read request for a block filling the buffer
+func (pipeline *DataObjectReadPipeline) request(buffer []byte) {
+	metrics := pipeline.connection.GetMetrics()
+	if metrics != nil {
+		metrics.IncreaseCounterForDataObjectRead(1)
+	}
+
+	pipeline.requestChan <- connection.RequestResponsePair{
+		Request:  message.NewIRODSMessageReadDataObjectRequest(pipeline.handle.FileDescriptor, len(buffer)),
+		Response: &message.IRODSMessageReadDataObjectResponse{},
+		BsBuffer: buffer,
+	}
+	pipeline.outstanding++
+}
+
+// receive receives a response for the oldest request, returns data read
+// responses received after an error or EOF are discarded, but they still move the server-side offset
+func (pipeline *DataObjectReadPipeline) receive() ([]byte, bool) {
+	rrPair, ok := <-pipeline.responseChan
+	if !ok {
+		return nil, false
+	}
+
+	pipeline.outstanding--
+
+	if rrPair.Error != nil {
+		if pipeline.lastErr == nil {
+			pipeline.lastErr = rrPair.Error
+		}
+		return nil, true
+	}
+
+	response := rrPair.Response.(*message.IRODSMessageReadDataObjectResponse)
+	resErr := response.CheckError()
+	if resErr != nil {
+		if pipeline.lastErr != nil {
+			return nil, true
+		}
+
+		if types.GetIRODSErrorCode(resErr) == common.CAT_NO_ROWS_FOUND {
+			pipeline.lastErr = xerrors.Errorf("failed to find the data object for path %s: %w", pipeline.handle.Path, types.NewFileNotFoundError(pipeline.handle.Path))
+			return nil, true
+		}
+
+		pipeline.lastErr = xerrors.Errorf("failed to read data object: %w", resErr)
+		return nil, true
+	}
+
+	readLen := len(response.Data)
+	pipeline.serverOffset += int64(readLen)
+
+	if pipeline.lastErr != nil || pipeline.eof {
+		// drain
+		return nil, true
+	}
+
+	if readLen < len(rrPair.BsBuffer) {
+		// EOF, following blocks will be empty
+		pipeline.eof = true
+	}
+
+	pipeline.lastBuffer = rrPair.BsBuffer
+	return rrPair.BsBuffer[:readLen], true
+}
+
+// Next returns data of the next block, io.EOF is returned if there is no more data
+// returned data is valid until the next call, as its buffer is reused for a request for a following block
+func (pipeline *DataObjectReadPipeline) Next() ([]byte, error) {
+	if pipeline.closed {
+		return nil, xerrors.Errorf("pipeline is already closed")
+	}
+
+	if pipeline.lastBuffer != nil {
+		buffer := pipeline.lastBuffer
+		pipeline.lastBuffer = nil
+
+		if pipeline.lastErr == nil && !pipeline.eof {
+			// keep the window full
+			pipeline.request(buffer)
+		}
+	}
+
+	if pipeline.lastErr != nil {
+		return nil, pipeline.lastErr
+	}
+
+	if pipeline.eof || pipeline.outstanding == 0 {
+		return nil, io.EOF
+	}
+
+	data, _ := pipeline.receive()
+	if pipeline.lastErr != nil {
+		return nil, pipeline.lastErr
+	}
+
+	if len(data) == 0 {
+		return nil, io.EOF
+	}
+
+	return data, nil
+}
+
+// Close receives responses for outstanding requests and unlocks the connection
+// returns the offset of the server-side file descriptor, it is not reliable if the connection failed
+func (pipeline *DataObjectReadPipeline) Close() (int64, error) {
+	if pipeline.closed {
+		return pipeline.serverOffset, nil
+	}
+
+	pipeline.closed = true
+	pipeline.lastBuffer = nil
+
+	close(pipeline.requestChan)
+
+	for {
+		_, ok := pipeline.receive()
+		if !ok {
+			break
+		}
+	}
+
+	pipeline.connection.Unlock()
+
+	if pipeline.lastErr != nil {
+		return pipeline.serverOffset, pipeline.lastErr
+	}
+
+	return pipeline.serverOffset, nil
+}
@@ -549,7 +549,7 @@ func DownloadDataObjectFromResourceServer(session *session.IRODSSession, irodsPa
 		resource = account.DefaultResource
 	}
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
@@ -666,7 +666,7 @@ func UploadDataObjectToResourceServer(session *session.IRODSSession, localPath s
 
 	fileLength := stat.Size()
 
-	conn, err := session.AcquireConnection()
+	conn, err := acquireTransferConnection(session)
 	if err != nil {
 		return xerrors.Errorf("failed to get connection: %w", err)
 	}
@@ -0,0 +1,321 @@
+package fs
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"golang.org/x/xerrors"
+)
+
+// queryCollections queries collections matching conditions added by addConditions, the connection must be locked
+func queryCollections(conn *connection.IRODSConnection, addConditions func(query *message.IRODSMessageQueryRequest)) ([]*types.IRODSCollection, error) {
+	collections := []*types.IRODSCollection{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_COLL_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_CREATE_TIME, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_MODIFY_TIME, 1)
+
+		addConditions(query)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a collection query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received collection query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive collection attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedCollections := make([]*types.IRODSCollection, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive collection rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedCollections[row] == nil {
+					// create a new
+					pagenatedCollections[row] = &types.IRODSCollection{
+						ID:         -1,
+						Path:       "",
+						Name:       "",
+						Owner:      "",
+						CreateTime: time.Time{},
+						ModifyTime: time.Time{},
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_COLL_ID):
+					cID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse collection id '%s': %w", value, err)
+					}
+					pagenatedCollections[row].ID = cID
+				case int(common.ICAT_COLUMN_COLL_NAME):
+					pagenatedCollections[row].Path = value
+					pagenatedCollections[row].Name = util.GetIRODSPathFileName(value)
+				case int(common.ICAT_COLUMN_COLL_OWNER_NAME):
+					pagenatedCollections[row].Owner = value
+				case int(common.ICAT_COLUMN_COLL_CREATE_TIME):
+					cT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
+					}
+					pagenatedCollections[row].CreateTime = cT
+				case int(common.ICAT_COLUMN_COLL_MODIFY_TIME):
+					mT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
+					}
+					pagenatedCollections[row].ModifyTime = mT
+				default:
+					// ignore
+				}
+			}
+		}
+
+		collections = append(collections, pagenatedCollections...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	return collections, nil
+}
+
+// queryDataObjectReplicas queries replicas of data objects matching conditions added by addConditions, the connection must be locked
+// returns a data object having a single replica per row, in any collection, so paths are set
+func queryDataObjectReplicas(conn *connection.IRODSConnection, addConditions func(query *message.IRODSMessageQueryRequest)) ([]*types.IRODSDataObject, error) {
+	dataObjects := []*types.IRODSDataObject{}
+	// collection names are returned in a separate attribute, keep them to build paths
+	collectionNames := []string{}
+
+	continueQuery := true
+	continueIndex := 0
+	for continueQuery {
+		// data object
+		query := message.NewIRODSMessageQueryRequest(common.MaxQueryRows, continueIndex, 0, 0)
+		query.AddSelect(common.ICAT_COLUMN_D_DATA_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_COLL_ID, 1)
+		query.AddSelect(common.ICAT_COLUMN_COLL_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_DATA_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_DATA_SIZE, 1)
+		query.AddSelect(common.ICAT_COLUMN_DATA_TYPE_NAME, 1)
+
+		// replica
+		query.AddSelect(common.ICAT_COLUMN_DATA_REPL_NUM, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_OWNER_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_DATA_CHECKSUM, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_REPL_STATUS, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_RESC_NAME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_DATA_PATH, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_RESC_HIER, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_CREATE_TIME, 1)
+		query.AddSelect(common.ICAT_COLUMN_D_MODIFY_TIME, 1)
+
+		addConditions(query)
+
+		queryResult := message.IRODSMessageQueryResponse{}
+		err := conn.Request(query, &queryResult, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to receive a data object query result message: %w", err)
+		}
+
+		err = queryResult.CheckError()
+		if err != nil {
+			if types.GetIRODSErrorCode(err) == common.CAT_NO_ROWS_FOUND {
+				// empty
+				break
+			}
+			return nil, xerrors.Errorf("received data object query error: %w", err)
+		}
+
+		if queryResult.RowCount == 0 {
+			break
+		}
+
+		if queryResult.AttributeCount > len(queryResult.SQLResult) {
+			return nil, xerrors.Errorf("failed to receive data object attributes - requires %d, but received %d attributes", queryResult.AttributeCount, len(queryResult.SQLResult))
+		}
+
+		pagenatedDataObjects := make([]*types.IRODSDataObject, queryResult.RowCount)
+		pagenatedCollectionNames := make([]string, queryResult.RowCount)
+
+		for attr := 0; attr < queryResult.AttributeCount; attr++ {
+			sqlResult := queryResult.SQLResult[attr]
+			if len(sqlResult.Values) != queryResult.RowCount {
+				return nil, xerrors.Errorf("failed to receive data object rows - requires %d, but received %d attributes", queryResult.RowCount, len(sqlResult.Values))
+			}
+
+			for row := 0; row < queryResult.RowCount; row++ {
+				value := sqlResult.Values[row]
+
+				if pagenatedDataObjects[row] == nil {
+					// create a new
+					replica := &types.IRODSReplica{
+						Number:            -1,
+						Owner:             "",
+						Checksum:          nil,
+						Status:            "",
+						ResourceName:      "",
+						Path:              "",
+						ResourceHierarchy: "",
+						CreateTime:        time.Time{},
+						ModifyTime:        time.Time{},
+					}
+
+					pagenatedDataObjects[row] = &types.IRODSDataObject{
+						ID:           -1,
+						CollectionID: -1,
+						Path:         "",
+						Name:         "",
+						Size:         0,
+						DataType:     "",
+						Replicas:     []*types.IRODSReplica{replica},
+					}
+				}
+
+				switch sqlResult.AttributeIndex {
+				case int(common.ICAT_COLUMN_D_DATA_ID):
+					objID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse data object id '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].ID = objID
+				case int(common.ICAT_COLUMN_D_COLL_ID):
+					collID, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse collection id '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].CollectionID = collID
+				case int(common.ICAT_COLUMN_COLL_NAME):
+					pagenatedCollectionNames[row] = value
+				case int(common.ICAT_COLUMN_DATA_NAME):
+					pagenatedDataObjects[row].Name = value
+				case int(common.ICAT_COLUMN_DATA_SIZE):
+					objSize, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse data object size '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].Size = objSize
+				case int(common.ICAT_COLUMN_DATA_TYPE_NAME):
+					pagenatedDataObjects[row].DataType = value
+				case int(common.ICAT_COLUMN_DATA_REPL_NUM):
+					repNum, err := strconv.ParseInt(value, 10, 64)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse data object replica number '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].Replicas[0].Number = repNum
+				case int(common.ICAT_COLUMN_D_OWNER_NAME):
+					pagenatedDataObjects[row].Replicas[0].Owner = value
+				case int(common.ICAT_COLUMN_D_DATA_CHECKSUM):
+					checksum, err := types.CreateIRODSChecksum(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse data object checksum '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].Replicas[0].Checksum = checksum
+				case int(common.ICAT_COLUMN_D_REPL_STATUS):
+					pagenatedDataObjects[row].Replicas[0].Status = value
+				case int(common.ICAT_COLUMN_D_RESC_NAME):
+					pagenatedDataObjects[row].Replicas[0].ResourceName = value
+				case int(common.ICAT_COLUMN_D_DATA_PATH):
+					pagenatedDataObjects[row].Replicas[0].Path = value
+				case int(common.ICAT_COLUMN_D_RESC_HIER):
+					pagenatedDataObjects[row].Replicas[0].ResourceHierarchy = value
+				case int(common.ICAT_COLUMN_D_CREATE_TIME):
+					cT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse create time '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].Replicas[0].CreateTime = cT
+				case int(common.ICAT_COLUMN_D_MODIFY_TIME):
+					mT, err := util.GetIRODSDateTime(value)
+					if err != nil {
+						return nil, xerrors.Errorf("failed to parse modify time '%s': %w", value, err)
+					}
+					pagenatedDataObjects[row].Replicas[0].ModifyTime = mT
+				default:
+					// ignore
+				}
+			}
+		}
+
+		dataObjects = append(dataObjects, pagenatedDataObjects...)
+		collectionNames = append(collectionNames, pagenatedCollectionNames...)
+
+		continueIndex = queryResult.ContinueIndex
+		if continueIndex == 0 {
+			continueQuery = false
+		}
+	}
+
+	for idx, object := range dataObjects {
+		object.Path = util.MakeIRODSPath(collectionNames[idx], object.Name)
+	}
+
+	return dataObjects, nil
+}
+
+// mergeDataObjectReplicas merges data objects having a single replica into a data object per ID
+// a replica replaces the one kept if replace returns true
+func mergeDataObjectReplicas(dataObjects []*types.IRODSDataObject, replace func(kept *types.IRODSReplica, replica *types.IRODSReplica) bool) []*types.IRODSDataObject {
+	mergedDataObjectsMap := map[int64]*types.IRODSDataObject{}
+
+	for _, object := range dataObjects {
+		existingObj, exists := mergedDataObjectsMap[object.ID]
+		if exists {
+			// compare and replace
+			if len(existingObj.Replicas) == 0 {
+				// replace
+				mergedDataObjectsMap[object.ID] = object
+			} else if len(object.Replicas) > 0 {
+				if replace(existingObj.Replicas[0], object.Replicas[0]) {
+					mergedDataObjectsMap[object.ID] = object
+				}
+			}
+		} else {
+			// add
+			mergedDataObjectsMap[object.ID] = object
+		}
+	}
+
+	// convert map to array
+	mergedDataObjects := []*types.IRODSDataObject{}
+	for _, object := range mergedDataObjectsMap {
+		mergedDataObjects = append(mergedDataObjects, object)
+	}
+
+	return mergedDataObjects
+}
@@ -2,6 +2,7 @@ package fs
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/connection"
@@ -11,6 +12,51 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// GetServerInfo returns information of the server connected
+func GetServerInfo(conn *connection.IRODSConnection) (*types.IRODSServerInfo, error) {
+	if conn == nil || !conn.IsConnected() {
+		return nil, xerrors.Errorf("connection is nil or disconnected")
+	}
+
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	req := message.NewIRODSMessageGetMiscServerInfoRequest()
+	resp := message.IRODSMessageGetMiscServerInfoResponse{}
+	err := conn.RequestAndCheck(req, &resp, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get server info: %w", err)
+	}
+
+	serverType := types.IRODSServerTypeConsumer
+	if resp.ServerType == common.RCAT_ENABLED {
+		serverType = types.IRODSServerTypeProvider
+	}
+
+	bootTime := time.Time{}
+	if resp.ServerBootTime > 0 {
+		bootTime = time.Unix(resp.ServerBootTime, 0)
+	}
+
+	serverInfo := &types.IRODSServerInfo{
+		Type:           serverType,
+		ReleaseVersion: resp.ReleaseVersion,
+		APIVersion:     resp.APIVersion,
+		BootTime:       bootTime,
+		Zone:           resp.Zone,
+	}
+
+	// reconnection info is given at connection startup
+	version := conn.GetVersion()
+	if version != nil {
+		serverInfo.ReconnectPort = version.ReconnectPort
+		serverInfo.ReconnectAddr = version.ReconnectAddr
+	}
+
+	return serverInfo, nil
+}
+
 // StatProcess stats processes.
 func StatProcess(conn *connection.IRODSConnection, address string, zone string) ([]*types.IRODSProcess, error) {
 	// lock the connection
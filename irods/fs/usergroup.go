@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cyverse/go-irodsclient/irods/auth"
 	"github.com/cyverse/go-irodsclient/irods/common"
 	"github.com/cyverse/go-irodsclient/irods/connection"
 	"github.com/cyverse/go-irodsclient/irods/message"
@@ -505,14 +506,7 @@ func ChangeUserPassword(conn *connection.IRODSConnection, username string, zone
 
 	userZoneName := fmt.Sprintf("%s#%s", username, zone)
 
-	account := conn.GetAccount()
-
-	oldPassword := account.Password
-	if account.AuthenticationScheme == types.AuthSchemePAM {
-		oldPassword = conn.GetPAMToken()
-	}
-
-	scrambledPassword := util.ObfuscateNewPassword(newPassword, oldPassword, conn.GetClientSignature())
+	scrambledPassword := util.ObfuscateNewPassword(newPassword, getLoginPassword(conn), conn.GetClientSignature())
 
 	req := message.NewIRODSMessageAdminRequest("modify", "user", userZoneName, "password", scrambledPassword, zone)
 
@@ -523,6 +517,48 @@ func ChangeUserPassword(conn *connection.IRODSConnection, username string, zone
 	return nil
 }
 
+// GetTemporaryPassword issues a temporary password of the user logged in
+func GetTemporaryPassword(conn *connection.IRODSConnection) (string, error) {
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	req := message.NewIRODSMessageGetTempPasswordRequest()
+	resp := message.IRODSMessageGetTempPasswordResponse{}
+	err := conn.RequestAndCheck(req, &resp, nil)
+	if err != nil {
+		return "", xerrors.Errorf("received get temporary password error: %w", err)
+	}
+
+	return auth.GenerateTemporaryPassword(resp.StringToHashWith, getLoginPassword(conn)), nil
+}
+
+// GetTemporaryPasswordForOther issues a temporary password of other user, only rodsadmin can issue
+func GetTemporaryPasswordForOther(conn *connection.IRODSConnection, username string) (string, error) {
+	// lock the connection
+	conn.Lock()
+	defer conn.Unlock()
+
+	req := message.NewIRODSMessageGetTempPasswordForOtherRequest(username)
+	resp := message.IRODSMessageGetTempPasswordForOtherResponse{}
+	err := conn.RequestAndCheck(req, &resp, nil)
+	if err != nil {
+		return "", xerrors.Errorf("received get temporary password for other user error: %w", err)
+	}
+
+	// hashed with the password of the admin logged in
+	return auth.GenerateTemporaryPassword(resp.StringToHashWith, getLoginPassword(conn)), nil
+}
+
+// getLoginPassword returns the password used to log in, the pam token for pam auth
+func getLoginPassword(conn *connection.IRODSConnection) string {
+	account := conn.GetAccount()
+	if account.AuthenticationScheme == types.AuthSchemePAM {
+		return conn.GetPAMToken()
+	}
+	return account.Password
+}
+
 // ChangeUserType changes the type / role of a user object
 func ChangeUserType(conn *connection.IRODSConnection, username string, zone string, newType string) error {
 	// lock the connection
@@ -0,0 +1,89 @@
+package message
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageAuthenticationRequest stores authentication request of iRODS authentication framework
+type IRODSMessageAuthenticationRequest struct {
+	Context map[string]interface{}
+}
+
+// NewIRODSMessageAuthenticationRequest creates a IRODSMessageAuthenticationRequest message
+func NewIRODSMessageAuthenticationRequest(context map[string]interface{}) *IRODSMessageAuthenticationRequest {
+	return &IRODSMessageAuthenticationRequest{
+		Context: context,
+	}
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessageAuthenticationRequest) GetBytes() ([]byte, error) {
+	jsonBody, err := json.Marshal(msg.Context)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to json: %w", err)
+	}
+
+	jsonBodyBin := base64.StdEncoding.EncodeToString(jsonBody)
+
+	binBytesBuf := IRODSMessageBinBytesBuf{
+		Length: len(jsonBody), // use original data's length
+		Data:   jsonBodyBin,
+	}
+
+	xmlBytes, err := xml.Marshal(binBytesBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageAuthenticationRequest) FromBytes(bytes []byte) error {
+	binBytesBuf := IRODSMessageBinBytesBuf{}
+	err := xml.Unmarshal(bytes, &binBytesBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+
+	jsonBody, err := base64.StdEncoding.DecodeString(binBytesBuf.Data)
+	if err != nil {
+		return xerrors.Errorf("failed to decode base64 data: %w", err)
+	}
+
+	err = json.Unmarshal(jsonBody, &msg.Context)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal json to irods message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageAuthenticationRequest) GetMessage() (*IRODSMessage, error) {
+	bytes, err := msg.GetBytes()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
+	}
+
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: bytes,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.AUTHENTICATION_APN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package message
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageAuthenticationResponse stores authentication response of iRODS authentication framework
+type IRODSMessageAuthenticationResponse struct {
+	Context map[string]interface{}
+
+	// stores error return
+	Result int
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageAuthenticationResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageAuthenticationResponse) FromBytes(data []byte) error {
+	binBytesBuf := IRODSMessageBinBytesBuf{}
+	err := xml.Unmarshal(data, &binBytesBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+
+	jsonBody, err := base64.StdEncoding.DecodeString(binBytesBuf.Data)
+	if err != nil {
+		return xerrors.Errorf("failed to decode base64 data: %w", err)
+	}
+
+	// remove trail \x00
+	jsonBody = bytes.TrimRight(jsonBody, "\x00")
+
+	err = json.Unmarshal(jsonBody, &msg.Context)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal json to irods message: %w", err)
+	}
+
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageAuthenticationResponse) FromMessage(msgIn *IRODSMessage) error {
+	if msgIn.Body == nil {
+		return xerrors.Errorf("empty message body")
+	}
+
+	msg.Result = int(msgIn.Body.IntInfo)
+
+	if msgIn.Body.Message != nil {
+		err := msg.FromBytes(msgIn.Body.Message)
+		if err != nil {
+			return xerrors.Errorf("failed to get irods message from message body: %w", err)
+		}
+	}
+
+	return nil
+}
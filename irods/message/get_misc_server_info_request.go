@@ -0,0 +1,37 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageGetMiscServerInfoRequest stores misc server info request
+type IRODSMessageGetMiscServerInfoRequest struct {
+	// empty structure
+}
+
+// NewIRODSMessageGetMiscServerInfoRequest creates a IRODSMessageGetMiscServerInfoRequest message
+func NewIRODSMessageGetMiscServerInfoRequest() *IRODSMessageGetMiscServerInfoRequest {
+	return &IRODSMessageGetMiscServerInfoRequest{}
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageGetMiscServerInfoRequest) GetMessage() (*IRODSMessage, error) {
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: nil,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.GET_MISC_SVR_INFO_AN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package message
+
+import (
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageGetMiscServerInfoResponse stores misc server info response
+type IRODSMessageGetMiscServerInfoResponse struct {
+	XMLName        xml.Name `xml:"MiscSvrInfo_PI"`
+	ServerType     int      `xml:"serverType"`
+	ServerBootTime int64    `xml:"serverBootTime"`
+	ReleaseVersion string   `xml:"relVersion"`
+	APIVersion     string   `xml:"apiVersion"`
+	Zone           string   `xml:"rodsZone"`
+
+	// stores error return
+	Result int `xml:"-"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageGetMiscServerInfoResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessageGetMiscServerInfoResponse) GetBytes() ([]byte, error) {
+	xmlBytes, err := xml.Marshal(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageGetMiscServerInfoResponse) FromBytes(bytes []byte) error {
+	err := xml.Unmarshal(bytes, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageGetMiscServerInfoResponse) FromMessage(msgIn *IRODSMessage) error {
+	if msgIn.Body == nil {
+		return xerrors.Errorf("empty message body")
+	}
+
+	msg.Result = int(msgIn.Body.IntInfo)
+
+	if msgIn.Body.Message != nil {
+		err := msg.FromBytes(msgIn.Body.Message)
+		if err != nil {
+			return xerrors.Errorf("failed to get irods message from message body: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package message
+
+import (
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageGetTempPasswordForOtherResponse stores temporary password response for other user
+type IRODSMessageGetTempPasswordForOtherResponse struct {
+	XMLName          xml.Name `xml:"getTempPasswordForOtherOut_PI"`
+	StringToHashWith string   `xml:"stringToHashWith"`
+
+	// stores error return
+	Result int `xml:"-"`
+}
+
+// CheckError returns error if server returned an error
+func (msg *IRODSMessageGetTempPasswordForOtherResponse) CheckError() error {
+	if msg.Result < 0 {
+		return types.NewIRODSError(common.ErrorCode(msg.Result))
+	}
+	return nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageGetTempPasswordForOtherResponse) FromBytes(bytes []byte) error {
+	err := xml.Unmarshal(bytes, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+	return nil
+}
+
+// FromMessage returns struct from IRODSMessage
+func (msg *IRODSMessageGetTempPasswordForOtherResponse) FromMessage(msgIn *IRODSMessage) error {
+	if msgIn.Body == nil {
+		return xerrors.Errorf("empty message body")
+	}
+
+	msg.Result = int(msgIn.Body.IntInfo)
+
+	if msgIn.Body.Message != nil {
+		err := msg.FromBytes(msgIn.Body.Message)
+		if err != nil {
+			return xerrors.Errorf("failed to get irods message from message body: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package message
+
+import (
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageGetTempPasswordRequest stores temporary password request
+type IRODSMessageGetTempPasswordRequest struct {
+	// empty structure
+}
+
+// NewIRODSMessageGetTempPasswordRequest creates a IRODSMessageGetTempPasswordRequest message
+func NewIRODSMessageGetTempPasswordRequest() *IRODSMessageGetTempPasswordRequest {
+	return &IRODSMessageGetTempPasswordRequest{}
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageGetTempPasswordRequest) GetMessage() (*IRODSMessage, error) {
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: nil,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.GET_TEMP_PASSWORD_AN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package message
+
+import (
+	"encoding/xml"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"golang.org/x/xerrors"
+)
+
+// IRODSMessageSwitchUserRequest stores switch user request
+type IRODSMessageSwitchUserRequest struct {
+	XMLName  xml.Name             `xml:"SwitchUserInp_PI"`
+	Username string               `xml:"username"`
+	Zone     string               `xml:"zone"`
+	KeyVals  IRODSMessageSSKeyVal `xml:"KeyValPair_PI"`
+}
+
+// NewIRODSMessageSwitchUserRequest creates a IRODSMessageSwitchUserRequest message
+func NewIRODSMessageSwitchUserRequest(username string, zone string) *IRODSMessageSwitchUserRequest {
+	return &IRODSMessageSwitchUserRequest{
+		Username: username,
+		Zone:     zone,
+		KeyVals: IRODSMessageSSKeyVal{
+			Length: 0,
+		},
+	}
+}
+
+// AddKeyVal adds a key-value pair
+func (msg *IRODSMessageSwitchUserRequest) AddKeyVal(key common.KeyWord, val string) {
+	msg.KeyVals.Add(string(key), val)
+}
+
+// GetBytes returns byte array
+func (msg *IRODSMessageSwitchUserRequest) GetBytes() ([]byte, error) {
+	xmlBytes, err := xml.Marshal(msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal irods message to xml: %w", err)
+	}
+	return xmlBytes, nil
+}
+
+// FromBytes returns struct from bytes
+func (msg *IRODSMessageSwitchUserRequest) FromBytes(bytes []byte) error {
+	err := xml.Unmarshal(bytes, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal xml to irods message: %w", err)
+	}
+	return nil
+}
+
+// GetMessage builds a message
+func (msg *IRODSMessageSwitchUserRequest) GetMessage() (*IRODSMessage, error) {
+	bytes, err := msg.GetBytes()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get bytes from irods message: %w", err)
+	}
+
+	msgBody := IRODSMessageBody{
+		Type:    RODS_MESSAGE_API_REQ_TYPE,
+		Message: bytes,
+		Error:   nil,
+		Bs:      nil,
+		IntInfo: int32(common.SWITCH_USER_APN),
+	}
+
+	msgHeader, err := msgBody.BuildHeader()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to build header from irods message: %w", err)
+	}
+
+	return &IRODSMessage{
+		Header: msgHeader,
+		Body:   &msgBody,
+	}, nil
+}
@@ -32,8 +32,9 @@ type IRODSMetrics struct {
 	bytesReceived uint64
 
 	// cache
-	cacheHit  uint64
-	cacheMiss uint64
+	cacheHit   uint64
+	cacheMiss  uint64
+	cacheStats map[string]*CacheStats // cache name => stats
 
 	// file handles - gauge
 	openFileHandles uint64
@@ -42,11 +43,18 @@ type IRODSMetrics struct {
 	connectionsOpened   uint64
 	connectionsOccupied uint64
 
+	// connection pool waits and evictions
+	connectionPoolWaits     uint64
+	connectionPoolEvictions uint64
+
 	// failures
 	requestResponseFailures uint64
 	connectionFailures      uint64
 	connectionPoolFailures  uint64
 
+	// retries
+	retries uint64
+
 	mutex sync.Mutex
 }
 
@@ -700,6 +708,90 @@ func (metrics *IRODSMetrics) GetAndClearCounterForCacheMiss() uint64 {
 	return miss
 }
 
+// CacheStats contains counters of a cache
+type CacheStats struct {
+	Hit      uint64
+	Miss     uint64
+	Eviction uint64
+}
+
+// getCacheStats returns stats of the named cache, creates if not exist
+func (metrics *IRODSMetrics) getCacheStats(name string) *CacheStats {
+	if metrics.cacheStats == nil {
+		metrics.cacheStats = map[string]*CacheStats{}
+	}
+
+	stats, ok := metrics.cacheStats[name]
+	if !ok {
+		stats = &CacheStats{}
+		metrics.cacheStats[name] = stats
+	}
+	return stats
+}
+
+// IncreaseCounterForNamedCacheHit increases the counter for cache hit of the named cache, also increases the counter for cache hit
+func (metrics *IRODSMetrics) IncreaseCounterForNamedCacheHit(name string, n uint64) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.getCacheStats(name).Hit += n
+	metrics.cacheHit += n
+}
+
+// IncreaseCounterForNamedCacheMiss increases the counter for cache miss of the named cache, also increases the counter for cache miss
+func (metrics *IRODSMetrics) IncreaseCounterForNamedCacheMiss(name string, n uint64) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.getCacheStats(name).Miss += n
+	metrics.cacheMiss += n
+}
+
+// IncreaseCounterForNamedCacheEviction increases the counter for cache eviction of the named cache
+func (metrics *IRODSMetrics) IncreaseCounterForNamedCacheEviction(name string, n uint64) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.getCacheStats(name).Eviction += n
+}
+
+// GetCacheStats returns the counters of the named cache
+func (metrics *IRODSMetrics) GetCacheStats(name string) CacheStats {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	if stats, ok := metrics.cacheStats[name]; ok {
+		return *stats
+	}
+	return CacheStats{}
+}
+
+// GetAllCacheStats returns the counters of all caches
+func (metrics *IRODSMetrics) GetAllCacheStats() map[string]CacheStats {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	allStats := map[string]CacheStats{}
+	for name, stats := range metrics.cacheStats {
+		allStats[name] = *stats
+	}
+	return allStats
+}
+
+// GetAndClearAllCacheStats returns the counters of all caches then clear
+func (metrics *IRODSMetrics) GetAndClearAllCacheStats() map[string]CacheStats {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	allStats := map[string]CacheStats{}
+	for name, stats := range metrics.cacheStats {
+		allStats[name] = *stats
+	}
+
+	metrics.cacheStats = nil
+	return allStats
+}
+
 // IncreaseOpenFileHandles increases the counter for open file handles
 func (metrics *IRODSMetrics) IncreaseCounterForOpenFileHandles(n uint64) {
 	metrics.mutex.Lock()
@@ -870,7 +962,91 @@ func (metrics *IRODSMetrics) GetAndClearCounterForConnectionPoolFailures() uint6
 	return failures
 }
 
+// IncreaseCounterForConnectionPoolWaits increases the counter for connection acquisitions waited in the pool queue
+func (metrics *IRODSMetrics) IncreaseCounterForConnectionPoolWaits(n uint64) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.connectionPoolWaits += n
+}
+
+// GetCounterForConnectionPoolWaits returns the counter for connection acquisitions waited in the pool queue
+func (metrics *IRODSMetrics) GetCounterForConnectionPoolWaits() uint64 {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	return metrics.connectionPoolWaits
+}
+
+// GetAndClearCounterForConnectionPoolWaits returns the counter for connection acquisitions waited in the pool queue then clear
+func (metrics *IRODSMetrics) GetAndClearCounterForConnectionPoolWaits() uint64 {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	waits := metrics.connectionPoolWaits
+	metrics.connectionPoolWaits = 0
+	return waits
+}
+
+// IncreaseCounterForConnectionPoolEvictions increases the counter for dead connections evicted by health checks
+func (metrics *IRODSMetrics) IncreaseCounterForConnectionPoolEvictions(n uint64) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.connectionPoolEvictions += n
+}
+
+// GetCounterForConnectionPoolEvictions returns the counter for dead connections evicted by health checks
+func (metrics *IRODSMetrics) GetCounterForConnectionPoolEvictions() uint64 {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	return metrics.connectionPoolEvictions
+}
+
+// GetAndClearCounterForConnectionPoolEvictions returns the counter for dead connections evicted by health checks then clear
+func (metrics *IRODSMetrics) GetAndClearCounterForConnectionPoolEvictions() uint64 {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	evictions := metrics.connectionPoolEvictions
+	metrics.connectionPoolEvictions = 0
+	return evictions
+}
+
+// IncreaseCounterForRetries increases the counter for retried operations
+func (metrics *IRODSMetrics) IncreaseCounterForRetries(n uint64) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.retries += n
+}
+
+// GetCounterForRetries returns the counter for retried operations
+func (metrics *IRODSMetrics) GetCounterForRetries() uint64 {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	return metrics.retries
+}
+
+// GetAndClearCounterForRetries returns the counter for retried operations then clear
+func (metrics *IRODSMetrics) GetAndClearCounterForRetries() uint64 {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	retries := metrics.retries
+	metrics.retries = 0
+	return retries
+}
+
 func (metrics *IRODSMetrics) Sum(other *IRODSMetrics) {
+	// copy first, other's cache stats may be updated concurrently
+	otherCacheStats := other.GetAllCacheStats()
+
+	other.mutex.Lock()
+	defer other.mutex.Unlock()
+
 	metrics.stat += other.stat
 	metrics.list += other.list
 	metrics.search += other.search
@@ -896,10 +1072,19 @@ func (metrics *IRODSMetrics) Sum(other *IRODSMetrics) {
 	metrics.bytesReceived += other.bytesReceived
 	metrics.cacheHit += other.cacheHit
 	metrics.cacheMiss += other.cacheMiss
+	for name, stats := range otherCacheStats {
+		myStats := metrics.getCacheStats(name)
+		myStats.Hit += stats.Hit
+		myStats.Miss += stats.Miss
+		myStats.Eviction += stats.Eviction
+	}
 	metrics.openFileHandles += other.openFileHandles
 	metrics.connectionsOpened += other.connectionsOpened
 	metrics.connectionsOccupied += other.connectionsOccupied
 	metrics.requestResponseFailures += other.requestResponseFailures
 	metrics.connectionFailures += other.connectionFailures
 	metrics.connectionPoolFailures += other.connectionPoolFailures
+	metrics.connectionPoolWaits += other.connectionPoolWaits
+	metrics.connectionPoolEvictions += other.connectionPoolEvictions
+	metrics.retries += other.retries
 }
@@ -2,6 +2,8 @@ package session
 
 import (
 	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
 )
 
 const (
@@ -19,6 +21,16 @@ const (
 	IRODSSessionTimeoutDefault = 5 * time.Minute
 	// IRODSSessionTCPBufferSizeDefault is a default value of tcp buffer size
 	IRODSSessionTCPBufferSizeDefault = 4 * 1024 * 1024
+	// IRODSSessionConnectionWaitTimeoutDefault is a default value of connection wait timeout, in-use connections are shared after the timeout
+	IRODSSessionConnectionWaitTimeoutDefault = 5 * time.Second
+	// IRODSSessionConnectionHealthCheckIntervalDefault is a default value of connection health check interval, 0 disables health checks
+	IRODSSessionConnectionHealthCheckIntervalDefault = 0
+	// IRODSSessionTCPKeepAliveDefault is a default value of tcp keepalive period, 0 uses the OS default
+	IRODSSessionTCPKeepAliveDefault = 0
+	// IRODSSessionEndpointSelectionPolicyDefault is a default policy to choose a catalog provider for new connections
+	IRODSSessionEndpointSelectionPolicyDefault = EndpointSelectionRoundRobin
+	// IRODSSessionEndpointBackoffDefault is a default period a failed catalog provider is not chosen
+	IRODSSessionEndpointBackoffDefault = 30 * time.Second
 )
 
 // IRODSSessionConfig is for session configuration
@@ -33,6 +45,27 @@ type IRODSSessionConfig struct {
 	ConnectionMaxIdle      int
 	TcpBufferSize          int
 	StartNewTransaction    bool
+	RetryPolicy            *RetryPolicy
+	// ConnectionWaitTimeout is how long AcquireConnection waits for a connection when all connections are occupied
+	// an in-use connection is shared after the timeout, set 0 to share without waiting
+	ConnectionWaitTimeout time.Duration
+	// ConnectionReservedHighPriority is the number of connections only high priority acquisitions can occupy
+	ConnectionReservedHighPriority int
+	// ConnectionHealthCheckInterval is how often idle connections are pinged, dead ones are replaced with new connections
+	ConnectionHealthCheckInterval time.Duration
+	// ConnectionValidateOnBorrow pings an idle connection before reuse, a dead one is replaced with a new connection
+	ConnectionValidateOnBorrow bool
+	// TCPKeepAlive is a period of TCP keepalive probes, negative disables TCP keepalive
+	TCPKeepAlive time.Duration
+	// Dialer dials the server, e.g., via a proxy created with connection.NewDialerFromEnvironment, dials directly if nil
+	// applies to redirected resource server connections too
+	Dialer connection.Dialer
+	// EndpointSelectionPolicy is a policy to choose one of catalog providers, the account and its AdditionalEndpoints, for new connections
+	EndpointSelectionPolicy EndpointSelectionPolicy
+	// EndpointBackoff is how long a catalog provider failed to connect is not chosen, new connections fail over to others
+	EndpointBackoff time.Duration
+	// PAMTokenRefreshHandler is called when a new pam token is issued using password, e.g., when the old one is expired
+	PAMTokenRefreshHandler PAMTokenRefreshHandler
 }
 
 // NewIRODSSessionConfig create a IRODSSessionConfig
@@ -42,31 +75,47 @@ func NewIRODSSessionConfig(applicationName string, connectionErrorTimeout time.D
 	}
 
 	return &IRODSSessionConfig{
-		ApplicationName:        applicationName,
-		ConnectionErrorTimeout: connectionErrorTimeout,
-		ConnectionLifespan:     connectionLifespan,
-		OperationTimeout:       operationTimeout,
-		ConnectionIdleTimeout:  idleTimeout,
-		ConnectionMax:          connectionMax,
-		ConnectionInitNumber:   connectionInitNumber,
-		ConnectionMaxIdle:      IRODSSessionConnectionMaxMin,
-		TcpBufferSize:          tcpBufferSize,
-		StartNewTransaction:    startNewTransaction,
+		ApplicationName:                applicationName,
+		ConnectionErrorTimeout:         connectionErrorTimeout,
+		ConnectionLifespan:             connectionLifespan,
+		OperationTimeout:               operationTimeout,
+		ConnectionIdleTimeout:          idleTimeout,
+		ConnectionMax:                  connectionMax,
+		ConnectionInitNumber:           connectionInitNumber,
+		ConnectionMaxIdle:              IRODSSessionConnectionMaxMin,
+		TcpBufferSize:                  tcpBufferSize,
+		StartNewTransaction:            startNewTransaction,
+		RetryPolicy:                    NewRetryPolicyWithDefault(),
+		ConnectionWaitTimeout:          IRODSSessionConnectionWaitTimeoutDefault,
+		ConnectionReservedHighPriority: 0,
+		ConnectionHealthCheckInterval:  IRODSSessionConnectionHealthCheckIntervalDefault,
+		ConnectionValidateOnBorrow:     false,
+		TCPKeepAlive:                   IRODSSessionTCPKeepAliveDefault,
+		EndpointSelectionPolicy:        IRODSSessionEndpointSelectionPolicyDefault,
+		EndpointBackoff:                IRODSSessionEndpointBackoffDefault,
 	}
 }
 
 // NewIRODSSessionConfigWithDefault create a IRODSSessionConfig with a default settings
 func NewIRODSSessionConfigWithDefault(applicationName string) *IRODSSessionConfig {
 	return &IRODSSessionConfig{
-		ApplicationName:        applicationName,
-		ConnectionErrorTimeout: IRODSSessionConnectionErrorTimeoutDefault,
-		ConnectionLifespan:     IRODSSessionConnectionLifespanDefault,
-		OperationTimeout:       IRODSSessionTimeoutDefault,
-		ConnectionIdleTimeout:  IRODSSessionTimeoutDefault,
-		ConnectionMax:          IRODSSessionConnectionMaxDefault,
-		ConnectionInitNumber:   IRODSSessionConnectionInitNumberDefault,
-		ConnectionMaxIdle:      IRODSSessionConnectionMaxMin,
-		TcpBufferSize:          IRODSSessionTCPBufferSizeDefault,
-		StartNewTransaction:    true,
+		ApplicationName:                applicationName,
+		ConnectionErrorTimeout:         IRODSSessionConnectionErrorTimeoutDefault,
+		ConnectionLifespan:             IRODSSessionConnectionLifespanDefault,
+		OperationTimeout:               IRODSSessionTimeoutDefault,
+		ConnectionIdleTimeout:          IRODSSessionTimeoutDefault,
+		ConnectionMax:                  IRODSSessionConnectionMaxDefault,
+		ConnectionInitNumber:           IRODSSessionConnectionInitNumberDefault,
+		ConnectionMaxIdle:              IRODSSessionConnectionMaxMin,
+		TcpBufferSize:                  IRODSSessionTCPBufferSizeDefault,
+		StartNewTransaction:            true,
+		RetryPolicy:                    NewRetryPolicyWithDefault(),
+		ConnectionWaitTimeout:          IRODSSessionConnectionWaitTimeoutDefault,
+		ConnectionReservedHighPriority: 0,
+		ConnectionHealthCheckInterval:  IRODSSessionConnectionHealthCheckIntervalDefault,
+		ConnectionValidateOnBorrow:     false,
+		TCPKeepAlive:                   IRODSSessionTCPKeepAliveDefault,
+		EndpointSelectionPolicy:        IRODSSessionEndpointSelectionPolicyDefault,
+		EndpointBackoff:                IRODSSessionEndpointBackoffDefault,
 	}
 }
@@ -0,0 +1,221 @@
+package session
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// EndpointSelectionPolicy is a policy to choose a catalog provider for new connections
+type EndpointSelectionPolicy string
+
+const (
+	// EndpointSelectionRoundRobin spreads new connections over healthy endpoints in turn
+	EndpointSelectionRoundRobin EndpointSelectionPolicy = "round_robin"
+	// EndpointSelectionLeastConnections chooses a healthy endpoint having the least open connections
+	EndpointSelectionLeastConnections EndpointSelectionPolicy = "least_connections"
+)
+
+// EndpointStatus is health of a catalog provider endpoint
+type EndpointStatus struct {
+	Endpoint      types.IRODSEndpoint
+	Healthy       bool
+	DownUntil     time.Time // zero if healthy
+	Connections   int       // open connections to the endpoint
+	Failures      int       // consecutive connection failures
+	LastError     error
+	LastErrorTime time.Time
+}
+
+// endpointState is a state of an endpoint
+type endpointState struct {
+	endpoint      types.IRODSEndpoint
+	downUntil     time.Time
+	failures      int
+	lastError     error
+	lastErrorTime time.Time
+}
+
+// endpointSelector chooses endpoints for new connections
+// an endpoint failed to connect is marked down for backoff period, new connections fail over to next endpoints
+type endpointSelector struct {
+	states      []*endpointState
+	policy      EndpointSelectionPolicy
+	backoff     time.Duration
+	next        int                                            // index of the endpoint to start with for round robin
+	connections map[*connection.IRODSConnection]*endpointState // connections opened, to count connections per endpoint
+	mutex       sync.Mutex
+}
+
+// newEndpointSelector creates an endpointSelector
+func newEndpointSelector(endpoints []types.IRODSEndpoint, policy EndpointSelectionPolicy, backoff time.Duration) *endpointSelector {
+	states := []*endpointState{}
+	for _, endpoint := range endpoints {
+		states = append(states, &endpointState{
+			endpoint: endpoint,
+		})
+	}
+
+	return &endpointSelector{
+		states:      states,
+		policy:      policy,
+		backoff:     backoff,
+		next:        0,
+		connections: map[*connection.IRODSConnection]*endpointState{},
+		mutex:       sync.Mutex{},
+	}
+}
+
+// countConnections returns the number of open connections per endpoint, forgets closed connections
+func (selector *endpointSelector) countConnections() map[*endpointState]int {
+	counts := map[*endpointState]int{}
+	for conn, state := range selector.connections {
+		if !conn.IsConnected() {
+			delete(selector.connections, conn)
+			continue
+		}
+
+		counts[state]++
+	}
+	return counts
+}
+
+// candidates returns endpoints in the order to try
+// healthy endpoints come first in the order of the policy, then endpoints marked down as last resort
+func (selector *endpointSelector) candidates() []*endpointState {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	now := time.Now()
+	healthy := []*endpointState{}
+	down := []*endpointState{}
+
+	start := selector.next
+	selector.next = (selector.next + 1) % len(selector.states)
+
+	for i := 0; i < len(selector.states); i++ {
+		state := selector.states[(start+i)%len(selector.states)]
+		if state.downUntil.After(now) {
+			down = append(down, state)
+		} else {
+			healthy = append(healthy, state)
+		}
+	}
+
+	if selector.policy == EndpointSelectionLeastConnections {
+		// ties are broken in round robin order
+		counts := selector.countConnections()
+		sort.SliceStable(healthy, func(i int, j int) bool {
+			return counts[healthy[i]] < counts[healthy[j]]
+		})
+	}
+
+	// the one recovering first is tried first
+	sort.SliceStable(down, func(i int, j int) bool {
+		return down[i].downUntil.Before(down[j].downUntil)
+	})
+
+	return append(healthy, down...)
+}
+
+// markUp marks the endpoint healthy
+func (selector *endpointSelector) markUp(state *endpointState, conn *connection.IRODSConnection) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	state.downUntil = time.Time{}
+	state.failures = 0
+
+	selector.connections[conn] = state
+}
+
+// forget stops counting the connection closed
+func (selector *endpointSelector) forget(conn *connection.IRODSConnection) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	delete(selector.connections, conn)
+}
+
+// markDown marks the endpoint down for backoff period
+func (selector *endpointSelector) markDown(state *endpointState, err error) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	now := time.Now()
+	state.downUntil = now.Add(selector.backoff)
+	state.failures++
+	state.lastError = err
+	state.lastErrorTime = now
+}
+
+// connect connects the connection to one of endpoints using connectFunc
+// the endpoint of the connection is set only when there are multiple endpoints, otherwise the account is used as is
+// errors other than connection errors, such as auth errors, are returned without failover
+func (selector *endpointSelector) connect(conn *connection.IRODSConnection, connectFunc func() error) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "endpointSelector",
+		"function": "connect",
+	})
+
+	var lastErr error
+	for _, state := range selector.candidates() {
+		if len(selector.states) > 1 {
+			endpoint := state.endpoint
+			conn.SetEndpoint(&endpoint)
+		}
+
+		err := connectFunc()
+		if err == nil {
+			selector.markUp(state, conn)
+			return nil
+		}
+
+		if !types.IsConnectionError(err) {
+			return err
+		}
+
+		logger.WithError(err).Debugf("Marking endpoint %s down", state.endpoint.GetAddress())
+		selector.markDown(state, err)
+		lastErr = err
+	}
+
+	if len(selector.states) == 1 {
+		return lastErr
+	}
+	return xerrors.Errorf("failed to connect to any of %d endpoints: %w", len(selector.states), lastErr)
+}
+
+// getStatus returns status of endpoints
+func (selector *endpointSelector) getStatus() []EndpointStatus {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	now := time.Now()
+	counts := selector.countConnections()
+
+	statuses := []EndpointStatus{}
+	for _, state := range selector.states {
+		status := EndpointStatus{
+			Endpoint:      state.endpoint,
+			Healthy:       !state.downUntil.After(now),
+			Connections:   counts[state],
+			Failures:      state.failures,
+			LastError:     state.lastError,
+			LastErrorTime: state.lastErrorTime,
+		}
+
+		if !status.Healthy {
+			status.DownUntil = state.downUntil
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"sync"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// PAMTokenRefreshHandler is an handler that is called when a new pam token is issued, e.g., when the old one is expired
+// the handler can persist the new token, e.g., using ICommandsEnvironmentManager.SavePAMToken
+type PAMTokenRefreshHandler func(pamToken string)
+
+// PAMTokenConnection is a connection created with the account of PAMTokenRefresher, e.g., *connection.IRODSConnection
+type PAMTokenConnection interface {
+	Connect() error
+}
+
+// PAMTokenRefresher connects connections, and refreshes the pam token of the account when it is expired
+// connections share the account, so login with password is serialized
+type PAMTokenRefresher struct {
+	account *types.IRODSAccount
+	handler PAMTokenRefreshHandler
+	mutex   sync.RWMutex
+}
+
+// NewPAMTokenRefresher creates a PAMTokenRefresher
+func NewPAMTokenRefresher(account *types.IRODSAccount, handler PAMTokenRefreshHandler) *PAMTokenRefresher {
+	return &PAMTokenRefresher{
+		account: account,
+		handler: handler,
+		mutex:   sync.RWMutex{},
+	}
+}
+
+// canRefresh returns true if a new pam token can be issued using password
+func (refresher *PAMTokenRefresher) canRefresh() bool {
+	return refresher.account.AuthenticationScheme == types.AuthSchemePAM && len(refresher.account.Password) > 0
+}
+
+// Connect connects the connection created with the account
+// if the pam token is expired, a new pam token is issued using password, and the handler is called with the new token
+func (refresher *PAMTokenRefresher) Connect(conn PAMTokenConnection) error {
+	if refresher.account.AuthenticationScheme != types.AuthSchemePAM {
+		return conn.Connect()
+	}
+
+	refresher.mutex.RLock()
+	pamToken := refresher.account.PamToken
+	if len(pamToken) == 0 {
+		// login with password issues a new token
+		refresher.mutex.RUnlock()
+		return refresher.refresh(conn, pamToken)
+	}
+
+	err := conn.Connect()
+	refresher.mutex.RUnlock()
+
+	if err != nil && types.IsAuthError(err) && refresher.canRefresh() {
+		// the token may be expired
+		return refresher.refresh(conn, pamToken)
+	}
+	return err
+}
+
+// refresh connects with a new pam token if the token is not refreshed by others yet
+func (refresher *PAMTokenRefresher) refresh(conn PAMTokenConnection, expiredPAMToken string) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "PAMTokenRefresher",
+		"function": "refresh",
+	})
+
+	refresher.mutex.Lock()
+
+	if refresher.account.PamToken != expiredPAMToken {
+		// refreshed already
+		err := conn.Connect()
+		refresher.mutex.Unlock()
+		return err
+	}
+
+	if len(expiredPAMToken) > 0 {
+		logger.Debug("Refreshing expired pam token")
+	}
+
+	refresher.account.PamToken = ""
+	err := conn.Connect()
+	if err != nil {
+		// keep the old token for next trials
+		refresher.account.PamToken = expiredPAMToken
+		refresher.mutex.Unlock()
+		return xerrors.Errorf("failed to issue a new pam token: %w", err)
+	}
+
+	pamToken := refresher.account.PamToken
+	refresher.mutex.Unlock()
+
+	// call the handler without holding the lock, as the handler may update other accounts
+	if refresher.handler != nil && len(pamToken) > 0 && pamToken != expiredPAMToken {
+		refresher.handler(pamToken)
+	}
+	return nil
+}
+
+// SetPAMToken sets the pam token refreshed using another account
+func (refresher *PAMTokenRefresher) SetPAMToken(pamToken string) {
+	if refresher.account.AuthenticationScheme != types.AuthSchemePAM {
+		return
+	}
+
+	refresher.mutex.Lock()
+	defer refresher.mutex.Unlock()
+
+	refresher.account.PamToken = pamToken
+}
@@ -2,6 +2,7 @@ package session
 
 import (
 	"container/list"
+	"context"
 	"sync"
 	"time"
 
@@ -13,6 +14,22 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// connectionPingTimeout is a timeout of a ping checking a connection is alive
+	// it is shorter than operation timeout, so a dead connection doesn't block health checks and acquisitions for long
+	connectionPingTimeout = 5 * time.Second
+)
+
+// ConnectionPriority is a priority of connection acquisition
+type ConnectionPriority int
+
+const (
+	// ConnectionPriorityLow is for long-running operations, such as data transfers
+	ConnectionPriorityLow ConnectionPriority = iota
+	// ConnectionPriorityHigh is for short operations, such as metadata operations
+	ConnectionPriorityHigh
+)
+
 // ConnectionPoolConfig is for connection pool configuration
 type ConnectionPoolConfig struct {
 	Account          *types.IRODSAccount
@@ -24,14 +41,40 @@ type ConnectionPoolConfig struct {
 	IdleTimeout      time.Duration // if there's no activity on a connection for the timeout time, the connection will die
 	OperationTimeout time.Duration // if there's no response for the timeout time, the request will fail
 	TcpBufferSize    int
+	// ReservedHighPriority is the number of connections that only high priority acquisitions can occupy
+	// so short operations are not starved by long-running operations occupying all connections
+	ReservedHighPriority int
+	TCPKeepAlive         time.Duration     // TCP keepalive period, 0 uses the OS default, negative disables
+	HealthCheckInterval  time.Duration     // idle connections are pinged periodically and dead ones are replaced, 0 disables
+	ValidateOnBorrow     bool              // idle connections are pinged before reuse
+	Dialer               connection.Dialer // dials the server, e.g., via a proxy, dials directly if nil
+	// Endpoints are catalog providers to connect to, Host and Port of Account are used if empty
+	Endpoints               []types.IRODSEndpoint
+	EndpointSelectionPolicy EndpointSelectionPolicy
+	EndpointBackoff         time.Duration // failed endpoints are not chosen for the period
+	// PAMTokenRefreshHandler is called when a new pam token is issued using password, e.g., when the old one is expired
+	PAMTokenRefreshHandler PAMTokenRefreshHandler
+}
+
+// connectionPoolWaiter is an acquisition waiting for a connection
+type connectionPoolWaiter struct {
+	priority ConnectionPriority
+	ready    chan bool // closed when a slot is granted or the pool is released
+	granted  bool
 }
 
 // ConnectionPool is a struct for connection pool
+// acquisitions that can't occupy a connection wait in FIFO queues, one per priority
+// high priority acquisitions are served first
 type ConnectionPool struct {
 	config              *ConnectionPoolConfig
 	idleConnections     *list.List // list of *connection.IRODSConnection
 	occupiedConnections map[*connection.IRODSConnection]bool
+	reserved            int          // slots granted, but connections are not taken or dialed yet
+	waiters             []*list.List // list of *connectionPoolWaiter, indexed by priority
 	metrics             *metrics.IRODSMetrics
+	pamTokenRefresher   *PAMTokenRefresher
+	endpointSelector    *endpointSelector
 	mutex               sync.Mutex
 	terminateChan       chan bool
 	terminated          bool
@@ -39,11 +82,20 @@ type ConnectionPool struct {
 
 // NewConnectionPool creates a new ConnectionPool
 func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetrics) (*ConnectionPool, error) {
+	endpoints := config.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = config.Account.GetEndpoints()
+	}
+
 	pool := &ConnectionPool{
 		config:              config,
 		idleConnections:     list.New(),
 		occupiedConnections: map[*connection.IRODSConnection]bool{},
+		reserved:            0,
+		waiters:             []*list.List{list.New(), list.New()},
 		metrics:             metrics,
+		pamTokenRefresher:   NewPAMTokenRefresher(config.Account, config.PAMTokenRefreshHandler),
+		endpointSelector:    newEndpointSelector(endpoints, config.EndpointSelectionPolicy, config.EndpointBackoff),
 		mutex:               sync.Mutex{},
 		terminateChan:       make(chan bool),
 		terminated:          false,
@@ -65,6 +117,7 @@ func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetri
 			case <-ticker.C:
 				pool.mutex.Lock()
 
+				expiredConns := []*connection.IRODSConnection{}
 				now := time.Now()
 				for {
 					elem := pool.idleConnections.Front()
@@ -78,11 +131,11 @@ func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetri
 						if idleConn.GetLastSuccessfulAccess().Add(pool.config.IdleTimeout).Before(now) {
 							// timeout
 							pool.idleConnections.Remove(elem)
-							idleConn.Disconnect()
+							expiredConns = append(expiredConns, idleConn)
 						} else if idleConn.GetCreationTime().Add(pool.config.Lifespan).Before(now) {
 							// too old
 							pool.idleConnections.Remove(elem)
-							idleConn.Disconnect()
+							expiredConns = append(expiredConns, idleConn)
 						} else {
 							break
 						}
@@ -93,25 +146,57 @@ func NewConnectionPool(config *ConnectionPoolConfig, metrics *metrics.IRODSMetri
 				}
 
 				pool.mutex.Unlock()
+
+				// disconnect outside of the lock
+				for _, expiredConn := range expiredConns {
+					pool.closeConnection(expiredConn)
+				}
 			}
 		}
 	}()
 
+	if pool.config.HealthCheckInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(pool.config.HealthCheckInterval)
+
+			for {
+				select {
+				case <-pool.terminateChan:
+					ticker.Stop()
+					return
+				case <-ticker.C:
+					pool.checkHealth()
+				}
+			}
+		}()
+	}
+
 	return pool, nil
 }
 
 // Release releases all resources
 func (pool *ConnectionPool) Release() {
 	pool.mutex.Lock()
-	defer pool.mutex.Unlock()
 
 	if pool.terminated {
+		pool.mutex.Unlock()
 		return
 	}
 
 	pool.terminated = true
-	pool.terminateChan <- true
+	close(pool.terminateChan)
+
+	// wake up all waiters, they will fail
+	for _, waiters := range pool.waiters {
+		for waiters.Len() > 0 {
+			waiterObj := waiters.Remove(waiters.Front())
+			if waiter, ok := waiterObj.(*connectionPoolWaiter); ok {
+				close(waiter.ready)
+			}
+		}
+	}
 
+	conns := []*connection.IRODSConnection{}
 	for pool.idleConnections.Len() > 0 {
 		elem := pool.idleConnections.Front()
 		if elem == nil {
@@ -120,143 +205,419 @@ func (pool *ConnectionPool) Release() {
 
 		idleConnObj := pool.idleConnections.Remove(elem)
 		if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-			idleConn.Disconnect()
+			conns = append(conns, idleConn)
 		}
 	}
 
 	for occupiedConn := range pool.occupiedConnections {
-		occupiedConn.Disconnect()
+		conns = append(conns, occupiedConn)
 	}
 
 	// clear
 	pool.occupiedConnections = map[*connection.IRODSConnection]bool{}
 
 	pool.metrics.ClearConnections()
+	pool.mutex.Unlock()
+
+	// disconnect outside of the lock
+	for _, conn := range conns {
+		pool.closeConnection(conn)
+	}
 }
 
 func (pool *ConnectionPool) init() error {
-	pool.mutex.Lock()
-	defer pool.mutex.Unlock()
-
 	// create connections
 	for i := 0; i < pool.config.InitialCap; i++ {
-		newConn := connection.NewIRODSConnectionWithMetrics(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName, pool.metrics)
-		newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
-		err := newConn.Connect()
+		newConn, err := pool.newConnection()
 		if err != nil {
-			pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
-			return xerrors.Errorf("failed to connect to irods server: %w", err)
+			return err
 		}
 
+		pool.mutex.Lock()
 		pool.idleConnections.PushBack(newConn)
+		pool.mutex.Unlock()
 	}
 
 	return nil
 }
 
-// Get gets a new or an idle connection out of the pool
-// the boolean return value indicates if the returned conneciton is new (True) or existing idle (False)
-func (pool *ConnectionPool) Get() (*connection.IRODSConnection, bool, error) {
+// newConnection creates a new connection and connects to the server, must be called without holding the lock
+func (pool *ConnectionPool) newConnection() (*connection.IRODSConnection, error) {
+	newConn := connection.NewIRODSConnectionWithMetrics(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName, pool.metrics)
+	newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
+	newConn.SetTCPKeepAlive(pool.config.TCPKeepAlive)
+	newConn.SetDialer(pool.config.Dialer)
+	err := pool.endpointSelector.connect(newConn, func() error {
+		return pool.pamTokenRefresher.Connect(newConn)
+	})
+	if err != nil {
+		pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
+		return nil, xerrors.Errorf("failed to connect to irods server: %w", err)
+	}
+
+	return newConn, nil
+}
+
+// ping returns true if the connection responds to a ping in a short timeout, must be called without holding the lock
+func (pool *ConnectionPool) ping(conn *connection.IRODSConnection) bool {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "ConnectionPool",
+		"function": "ping",
+	})
+
+	if !conn.IsConnected() {
+		return false
+	}
+
+	conn.Lock()
+	defer conn.Unlock()
+
+	pingTimeout := connectionPingTimeout
+	if pool.config.OperationTimeout > 0 && pool.config.OperationTimeout < pingTimeout {
+		pingTimeout = pool.config.OperationTimeout
+	}
+
+	err := conn.PingWithTimeout(pingTimeout)
+	if err != nil {
+		logger.Debugf("connection is dead: %+v", err)
+		return false
+	}
+
+	return true
+}
+
+// closeConnection disconnects the connection and stops counting it for its endpoint
+// disconnecting may take time, so it must be called without holding the lock
+func (pool *ConnectionPool) closeConnection(conn *connection.IRODSConnection) {
+	if conn.IsConnected() {
+		conn.Disconnect()
+	}
+
+	pool.endpointSelector.forget(conn)
+}
+
+// evict closes a dead connection, must be called without holding the lock
+func (pool *ConnectionPool) evict(conn *connection.IRODSConnection) {
+	pool.closeConnection(conn)
+
+	pool.metrics.IncreaseCounterForConnectionPoolEvictions(1)
+}
+
+// checkHealth pings idle connections, evicts dead ones and replaces them with new connections
+func (pool *ConnectionPool) checkHealth() {
 	logger := log.WithFields(log.Fields{
 		"package":  "session",
 		"struct":   "ConnectionPool",
-		"function": "Get",
+		"function": "checkHealth",
 	})
 
 	pool.mutex.Lock()
-	defer pool.mutex.Unlock()
+	idleConns := []*connection.IRODSConnection{}
+	for elem := pool.idleConnections.Front(); elem != nil; elem = elem.Next() {
+		if idleConn, ok := elem.Value.(*connection.IRODSConnection); ok {
+			idleConns = append(idleConns, idleConn)
+		}
+	}
+	pool.mutex.Unlock()
+
+	evicted := 0
+	for _, idleConn := range idleConns {
+		// ping outside of the lock, the connection may be taken meanwhile
+		if pool.ping(idleConn) {
+			continue
+		}
+
+		pool.mutex.Lock()
+		found := false
+		for elem := pool.idleConnections.Front(); elem != nil; elem = elem.Next() {
+			if elem.Value == idleConn {
+				pool.idleConnections.Remove(elem)
+				found = true
+				break
+			}
+		}
+		pool.mutex.Unlock()
+
+		if found {
+			pool.evict(idleConn)
+			evicted++
+		}
+	}
+
+	if evicted > 0 {
+		logger.Debugf("Evicted %d dead idle connections", evicted)
+	}
+
+	// replace evicted connections
+	for i := 0; i < evicted; i++ {
+		newConn, err := pool.newConnection()
+		if err != nil {
+			logger.Warnf("failed to replace a dead idle connection: %+v", err)
+			return
+		}
+
+		pool.mutex.Lock()
+		if pool.terminated || pool.idleConnections.Len() >= pool.config.MaxIdle {
+			pool.mutex.Unlock()
+			pool.closeConnection(newConn)
+			return
+		}
+
+		pool.idleConnections.PushBack(newConn)
+		pool.mutex.Unlock()
+	}
+}
+
+// getCapacity returns the number of connections the priority can occupy
+func (pool *ConnectionPool) getCapacity(priority ConnectionPriority) int {
+	if priority == ConnectionPriorityHigh {
+		return pool.config.MaxCap
+	}
+
+	return pool.config.MaxCap - pool.config.ReservedHighPriority
+}
+
+// hasSlot returns true if the priority can occupy a connection now
+func (pool *ConnectionPool) hasSlot(priority ConnectionPriority) bool {
+	return len(pool.occupiedConnections)+pool.reserved < pool.getCapacity(priority)
+}
+
+// hasWaitersAhead returns true if there are waiters to be served before the priority
+func (pool *ConnectionPool) hasWaitersAhead(priority ConnectionPriority) bool {
+	for p := int(ConnectionPriorityHigh); p >= int(priority); p-- {
+		if pool.waiters[p].Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch grants slots to waiters, high priority first, in FIFO order
+func (pool *ConnectionPool) dispatch() {
+	for p := int(ConnectionPriorityHigh); p >= int(ConnectionPriorityLow); p-- {
+		waiters := pool.waiters[p]
+		for waiters.Len() > 0 && pool.hasSlot(ConnectionPriority(p)) {
+			waiterObj := waiters.Remove(waiters.Front())
+			if waiter, ok := waiterObj.(*connectionPoolWaiter); ok {
+				pool.reserved++
+				waiter.granted = true
+				close(waiter.ready)
+			}
+		}
+	}
+}
+
+// reserve reserves a slot, waits in the queue until ctx is done if wait is true
+func (pool *ConnectionPool) reserve(ctx context.Context, priority ConnectionPriority, wait bool) error {
+	if priority != ConnectionPriorityHigh {
+		priority = ConnectionPriorityLow
+	}
+
+	pool.mutex.Lock()
+
+	if pool.terminated {
+		pool.mutex.Unlock()
+		return xerrors.Errorf("failed to get a connection, the pool is released")
+	}
+
+	if !pool.hasWaitersAhead(priority) && pool.hasSlot(priority) {
+		pool.reserved++
+		pool.mutex.Unlock()
+		return nil
+	}
+
+	if !wait {
+		occupied := len(pool.occupiedConnections) + pool.reserved
+		pool.mutex.Unlock()
+		return types.NewConnectionPoolFullError(occupied, pool.getCapacity(priority))
+	}
+
+	waiter := &connectionPoolWaiter{
+		priority: priority,
+		ready:    make(chan bool),
+		granted:  false,
+	}
+	elem := pool.waiters[priority].PushBack(waiter)
+	pool.metrics.IncreaseCounterForConnectionPoolWaits(1)
+	pool.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		pool.mutex.Lock()
+		defer pool.mutex.Unlock()
+
+		if !waiter.granted {
+			return xerrors.Errorf("failed to get a connection, the pool is released")
+		}
+		return nil
+	case <-ctx.Done():
+		pool.mutex.Lock()
+		defer pool.mutex.Unlock()
+
+		if waiter.granted {
+			// granted just before, give it to others
+			pool.reserved--
+			pool.dispatch()
+		} else if !pool.terminated {
+			pool.waiters[priority].Remove(elem)
+		}
 
-	if len(pool.occupiedConnections) >= pool.config.MaxCap {
-		return nil, false, types.NewConnectionPoolFullError(len(pool.occupiedConnections), pool.config.MaxCap)
+		occupied := len(pool.occupiedConnections) + pool.reserved
+		return xerrors.Errorf("failed to get a connection before %s: %w", ctx.Err().Error(), types.NewConnectionPoolFullError(occupied, pool.getCapacity(priority)))
 	}
+}
+
+// occupy takes an idle connection or creates a new one with the slot reserved
+// connections are dialed outside of the lock, so other acquisitions are not blocked
+func (pool *ConnectionPool) occupy(reuseIdle bool) (*connection.IRODSConnection, bool, error) {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "ConnectionPool",
+		"function": "occupy",
+	})
+
+	pool.mutex.Lock()
 
-	var err error
 	// check if there's idle connection
-	if pool.idleConnections.Len() > 0 {
-		// there's idle connection
-		// LIFO
-		elem := pool.idleConnections.Back()
-		if elem != nil {
+	for pool.idleConnections.Len() > 0 {
+		if reuseIdle {
+			// LIFO
+			elem := pool.idleConnections.Back()
 			idleConnObj := pool.idleConnections.Remove(elem)
 			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
+				if idleConn.IsConnected() && pool.config.ValidateOnBorrow {
+					// validate outside of the lock, the slot is still reserved
+					pool.mutex.Unlock()
+					alive := pool.ping(idleConn)
+					pool.mutex.Lock()
+
+					if pool.terminated {
+						pool.reserved--
+						pool.mutex.Unlock()
+
+						pool.closeConnection(idleConn)
+						return nil, false, xerrors.Errorf("failed to get a connection, the pool is released")
+					}
+
+					if !alive {
+						logger.Warn("failed to reuse an idle connection because it is dead. evicting...")
+						pool.mutex.Unlock()
+						pool.evict(idleConn)
+						pool.mutex.Lock()
+						continue
+					}
+				}
+
 				if idleConn.IsConnected() {
 					// move to occupied connections
+					pool.reserved--
 					pool.occupiedConnections[idleConn] = true
-					logger.Debug("Reuse an idle connection")
+					pool.mutex.Unlock()
 
+					logger.Debug("Reuse an idle connection")
 					pool.metrics.IncreaseConnectionsOccupied(1)
 					return idleConn, false, nil
 				}
 
 				logger.Warn("failed to reuse an idle connection because it is already disconnected. discarding...")
+				pool.endpointSelector.forget(idleConn)
 			}
+		} else {
+			// close an idle connection to create a new one
+			elem := pool.idleConnections.Front()
+			idleConnObj := pool.idleConnections.Remove(elem)
+			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
+				pool.mutex.Unlock()
+				pool.closeConnection(idleConn)
+				pool.mutex.Lock()
+			}
+			break
 		}
 	}
 
+	pool.mutex.Unlock()
+
 	// create a new if not exists
-	newConn := connection.NewIRODSConnectionWithMetrics(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName, pool.metrics)
-	newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
-	err = newConn.Connect()
+	newConn, err := pool.newConnection()
+
+	pool.mutex.Lock()
+
+	pool.reserved--
+
 	if err != nil {
-		pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
-		return nil, false, xerrors.Errorf("failed to connect to irods server: %w", err)
+		// give the slot to others
+		pool.dispatch()
+		pool.mutex.Unlock()
+		return nil, false, err
+	}
+
+	if pool.terminated {
+		pool.mutex.Unlock()
+		pool.closeConnection(newConn)
+		return nil, false, xerrors.Errorf("failed to get a connection, the pool is released")
 	}
 
 	pool.occupiedConnections[newConn] = true
+	pool.mutex.Unlock()
+
 	logger.Debug("Created a new connection")
 	pool.metrics.IncreaseConnectionsOccupied(1)
 
 	return newConn, true, nil
 }
 
-// GetNew gets a new connection out of the pool
-func (pool *ConnectionPool) GetNew() (*connection.IRODSConnection, error) {
-	logger := log.WithFields(log.Fields{
-		"package":  "session",
-		"struct":   "ConnectionPool",
-		"function": "GetNew",
-	})
-
-	pool.mutex.Lock()
-	defer pool.mutex.Unlock()
+// Get gets a new or an idle connection out of the pool
+// the boolean return value indicates if the returned conneciton is new (True) or existing idle (False)
+// returns ConnectionPoolFullError immediately if all connections are occupied
+func (pool *ConnectionPool) Get() (*connection.IRODSConnection, bool, error) {
+	return pool.GetWithPriority(ConnectionPriorityHigh)
+}
 
-	if len(pool.occupiedConnections) >= pool.config.MaxCap {
-		return nil, types.NewConnectionPoolFullError(len(pool.occupiedConnections), pool.config.MaxCap)
+// GetWithPriority gets a new or an idle connection out of the pool
+// the boolean return value indicates if the returned conneciton is new (True) or existing idle (False)
+// returns ConnectionPoolFullError immediately if the priority cannot occupy a connection
+func (pool *ConnectionPool) GetWithPriority(priority ConnectionPriority) (*connection.IRODSConnection, bool, error) {
+	err := pool.reserve(context.Background(), priority, false)
+	if err != nil {
+		return nil, false, err
 	}
 
-	// full - close an idle connection and create a new one
-	if pool.idleConnections.Len() > 0 {
-		// close
-		elem := pool.idleConnections.Front()
-		if elem != nil {
-			idleConnObj := pool.idleConnections.Remove(elem)
-			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-				if idleConn.IsConnected() {
-					idleConn.Disconnect()
-				}
-			}
-		}
+	return pool.occupy(true)
+}
+
+// GetWithContext gets a new or an idle connection out of the pool
+// if all connections are occupied, waits in the queue of the priority until a connection is returned or ctx is done
+// the boolean return value indicates if the returned conneciton is new (True) or existing idle (False)
+func (pool *ConnectionPool) GetWithContext(ctx context.Context, priority ConnectionPriority) (*connection.IRODSConnection, bool, error) {
+	err := pool.reserve(ctx, priority, true)
+	if err != nil {
+		return nil, false, err
 	}
 
-	// create a new one
-	if len(pool.occupiedConnections)+pool.idleConnections.Len() < pool.config.MaxCap {
-		// create a new one
-		newConn := connection.NewIRODSConnection(pool.config.Account, pool.config.OperationTimeout, pool.config.ApplicationName)
-		newConn.SetTCPBufferSize(pool.config.TcpBufferSize)
-		err := newConn.Connect()
-		if err != nil {
-			pool.metrics.IncreaseCounterForConnectionPoolFailures(1)
-			return nil, xerrors.Errorf("failed to connect to irods server: %w", err)
-		}
+	return pool.occupy(true)
+}
 
-		pool.occupiedConnections[newConn] = true
-		logger.Debug("Created a new connection")
-		pool.metrics.IncreaseConnectionsOccupied(1)
+// GetWithTimeout gets a new or an idle connection out of the pool
+// if all connections are occupied, waits in the queue of the priority until a connection is returned or timeout
+// the boolean return value indicates if the returned conneciton is new (True) or existing idle (False)
+func (pool *ConnectionPool) GetWithTimeout(timeout time.Duration, priority ConnectionPriority) (*connection.IRODSConnection, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return pool.GetWithContext(ctx, priority)
+}
 
-		return newConn, nil
+// GetNew gets a new connection out of the pool
+func (pool *ConnectionPool) GetNew() (*connection.IRODSConnection, error) {
+	err := pool.reserve(context.Background(), ConnectionPriorityHigh, false)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, xerrors.Errorf("failed to create a new connection, no idle connections")
+	// close an idle connection and create a new one
+	conn, _, err := pool.occupy(false)
+	return conn, err
 }
 
 // Return returns the connection after use
@@ -268,7 +629,6 @@ func (pool *ConnectionPool) Return(conn *connection.IRODSConnection) error {
 	})
 
 	pool.mutex.Lock()
-	defer pool.mutex.Unlock()
 
 	// find it from occupied map
 	if _, ok := pool.occupiedConnections[conn]; ok {
@@ -277,18 +637,32 @@ func (pool *ConnectionPool) Return(conn *connection.IRODSConnection) error {
 		pool.metrics.DecreaseConnectionsOccupied(1)
 	} else {
 		// cannot find it from occupied map
+		pool.mutex.Unlock()
 		return xerrors.Errorf("failed to find the connection from occupied connection list")
 	}
 
+	// connections to close are disconnected outside of the lock
+	closeConns := []*connection.IRODSConnection{}
+	defer func() {
+		for _, closeConn := range closeConns {
+			pool.closeConnection(closeConn)
+		}
+	}()
+
+	// wake up waiters after the connection is returned to idle connections
+	defer pool.mutex.Unlock()
+	defer pool.dispatch()
+
 	if !conn.IsConnected() {
 		logger.Warn("failed to return the connection because it is already closed. discarding...")
+		pool.endpointSelector.forget(conn)
 		return nil
 	}
 
 	// do not return if the connection is too old
 	now := time.Now()
 	if conn.GetCreationTime().Add(pool.config.Lifespan).Before(now) {
-		conn.Disconnect()
+		closeConns = append(closeConns, conn)
 		logger.Debug("Returning and destroying an old connection")
 		return nil
 	}
@@ -302,7 +676,7 @@ func (pool *ConnectionPool) Return(conn *connection.IRODSConnection) error {
 		if elem != nil {
 			idleConnObj := pool.idleConnections.Remove(elem)
 			if idleConn, ok := idleConnObj.(*connection.IRODSConnection); ok {
-				idleConn.Disconnect()
+				closeConns = append(closeConns, idleConn)
 			}
 		}
 	}
@@ -315,16 +689,22 @@ func (pool *ConnectionPool) Return(conn *connection.IRODSConnection) error {
 // Discard discards the connection
 func (pool *ConnectionPool) Discard(conn *connection.IRODSConnection) {
 	pool.mutex.Lock()
-	defer pool.mutex.Unlock()
 
 	// find it from occupied map
 	delete(pool.occupiedConnections, conn)
 
 	pool.metrics.DecreaseConnectionsOccupied(1)
 
-	if conn.IsConnected() {
-		conn.Disconnect()
-	}
+	pool.dispatch()
+	pool.mutex.Unlock()
+
+	// disconnect outside of the lock
+	pool.closeConnection(conn)
+}
+
+// GetEndpointStatus returns status of catalog provider endpoints
+func (pool *ConnectionPool) GetEndpointStatus() []EndpointStatus {
+	return pool.endpointSelector.getStatus()
 }
 
 // OpenConnections returns total number of connections
@@ -356,5 +736,17 @@ func (pool *ConnectionPool) AvailableConnections() int {
 	pool.mutex.Lock()
 	defer pool.mutex.Unlock()
 
-	return pool.config.MaxCap - len(pool.occupiedConnections)
+	return pool.config.MaxCap - len(pool.occupiedConnections) - pool.reserved
+}
+
+// WaitingAcquisitions returns the number of acquisitions waiting for connections
+func (pool *ConnectionPool) WaitingAcquisitions() int {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	waiting := 0
+	for _, waiters := range pool.waiters {
+		waiting += waiters.Len()
+	}
+	return waiting
 }
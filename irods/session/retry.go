@@ -0,0 +1,206 @@
+package session
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// RetryMaxAttemptsDefault is a default value of max attempts, including the first attempt
+	RetryMaxAttemptsDefault = 3
+	// RetryInitialBackoffDefault is a default value of backoff before the first retry
+	RetryInitialBackoffDefault = 100 * time.Millisecond
+	// RetryMaxBackoffDefault is a default value of max backoff between retries
+	RetryMaxBackoffDefault = 5 * time.Second
+	// RetryBackoffMultiplierDefault is a default value of backoff multiplier
+	RetryBackoffMultiplierDefault = 2.0
+	// RetryJitterDefault is a default value of jitter, a fraction of backoff
+	RetryJitterDefault = 0.2
+)
+
+// RetryPolicy defines how failed operations are retried
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts including the first attempt, 1 or less disables retry
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound of backoff
+	MaxBackoff time.Duration
+	// BackoffMultiplier is multiplied to backoff at every retry
+	BackoffMultiplier float64
+	// Jitter is a fraction of backoff randomly added or subtracted, between 0 and 1
+	Jitter float64
+	// RetryMutatingOperations allows to retry operations that change data, such as create, delete, rename and write
+	// those operations may have been applied on the server before the failure
+	RetryMutatingOperations bool
+}
+
+// NewRetryPolicy creates a RetryPolicy
+func NewRetryPolicy(maxAttempts int, initialBackoff time.Duration, maxBackoff time.Duration, retryMutatingOperations bool) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:             maxAttempts,
+		InitialBackoff:          initialBackoff,
+		MaxBackoff:              maxBackoff,
+		BackoffMultiplier:       RetryBackoffMultiplierDefault,
+		Jitter:                  RetryJitterDefault,
+		RetryMutatingOperations: retryMutatingOperations,
+	}
+}
+
+// NewRetryPolicyWithDefault creates a RetryPolicy with default settings
+// mutating operations are not retried
+func NewRetryPolicyWithDefault() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:             RetryMaxAttemptsDefault,
+		InitialBackoff:          RetryInitialBackoffDefault,
+		MaxBackoff:              RetryMaxBackoffDefault,
+		BackoffMultiplier:       RetryBackoffMultiplierDefault,
+		Jitter:                  RetryJitterDefault,
+		RetryMutatingOperations: false,
+	}
+}
+
+// NewRetryPolicyNoRetry creates a RetryPolicy that does not retry
+func NewRetryPolicyNoRetry() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 1,
+	}
+}
+
+// GetMaxAttempts returns max attempts for an operation
+func (policy *RetryPolicy) GetMaxAttempts(mutating bool) int {
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return 1
+	}
+
+	if mutating && !policy.RetryMutatingOperations {
+		return 1
+	}
+
+	return policy.MaxAttempts
+}
+
+// GetBackoff returns backoff before the given retry, retry starts from 1
+func (policy *RetryPolicy) GetBackoff(retry int) time.Duration {
+	if policy == nil || retry <= 0 || policy.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := policy.BackoffMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if policy.MaxBackoff > 0 && backoff > float64(policy.MaxBackoff) {
+		backoff = float64(policy.MaxBackoff)
+	}
+
+	if policy.Jitter > 0 {
+		jitter := math.Min(policy.Jitter, 1)
+		// random value between [-jitter, +jitter)
+		backoff += backoff * jitter * (rand.Float64()*2 - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// IsRetryable returns if the error is transient and the operation can be retried
+func (policy *RetryPolicy) IsRetryable(err error) bool {
+	return types.IsRetryableError(err)
+}
+
+// ConnectionFunc is a function that runs an operation with a connection
+type ConnectionFunc func(conn *connection.IRODSConnection) error
+
+// RunWithRetry runs an idempotent operation, such as stat, list, query and read, with a pooled connection
+// on transient failures, the operation is retried with a fresh connection according to the retry policy
+func (sess *IRODSSession) RunWithRetry(fn ConnectionFunc) error {
+	return sess.retry(sess.getConnectionFuncRunner(fn), false)
+}
+
+// RunMutatingWithRetry runs an operation that changes data with a pooled connection
+// the operation is retried only if the retry policy allows to retry mutating operations
+func (sess *IRODSSession) RunMutatingWithRetry(fn ConnectionFunc) error {
+	return sess.retry(sess.getConnectionFuncRunner(fn), true)
+}
+
+// Retry runs an idempotent operation that acquires connections by itself, such as download
+// on transient failures, the operation is retried according to the retry policy
+func (sess *IRODSSession) Retry(fn func() error) error {
+	return sess.retry(fn, false)
+}
+
+// RetryMutating runs an operation that changes data and acquires connections by itself, such as upload
+// the operation is retried only if the retry policy allows to retry mutating operations
+func (sess *IRODSSession) RetryMutating(fn func() error) error {
+	return sess.retry(fn, true)
+}
+
+// getConnectionFuncRunner returns a function that runs fn with a pooled connection
+// broken connections are discarded, so the next attempt will use a fresh connection
+func (sess *IRODSSession) getConnectionFuncRunner(fn ConnectionFunc) func() error {
+	return func() error {
+		conn, err := sess.AcquireConnection()
+		if err != nil {
+			return err
+		}
+
+		err = fn(conn)
+		if err == nil {
+			sess.ReturnConnection(conn)
+			return nil
+		}
+
+		// the connection is closed on socket failures
+		if !conn.IsConnected() {
+			sess.DiscardConnection(conn)
+			return xerrors.Errorf("connection is broken (%s): %w", err.Error(), types.NewConnectionError())
+		}
+
+		sess.ReturnConnection(conn)
+		return err
+	}
+}
+
+func (sess *IRODSSession) retry(fn func() error, mutating bool) error {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "IRODSSession",
+		"function": "retry",
+	})
+
+	policy := sess.config.RetryPolicy
+	maxAttempts := policy.GetMaxAttempts(mutating)
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			backoff := policy.GetBackoff(attempt)
+			logger.WithError(err).Debugf("retrying the operation in %s (attempt %d/%d)", backoff, attempt+1, maxAttempts)
+
+			sess.metrics.IncreaseCounterForRetries(1)
+			time.Sleep(backoff)
+		}
+
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if !policy.IsRetryable(err) {
+			return err
+		}
+	}
+
+	if maxAttempts > 1 {
+		return xerrors.Errorf("failed after %d attempts: %w", maxAttempts, err)
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -25,6 +26,7 @@ type IRODSSession struct {
 	poormansRollbackFail      bool
 	transactionFailureHandler TransactionFailureHandler
 	addressResolver           AddressResolver
+	pamTokenRefresher         *PAMTokenRefresher
 
 	lastConnectionError     error
 	lastConnectionErrorTime time.Time
@@ -68,20 +70,45 @@ func NewIRODSSessionWithAddressResolver(account *types.IRODSAccount, config *IRO
 
 	// resolve host address
 	poolAccount := *account
+	endpoints := account.GetEndpoints()
 	if addressResolver != nil {
 		poolAccount.Host = addressResolver(poolAccount.Host)
+
+		for idx := range endpoints {
+			endpoints[idx].Host = addressResolver(endpoints[idx].Host)
+		}
 	}
 
 	poolConfig := ConnectionPoolConfig{
-		Account:          &poolAccount,
-		ApplicationName:  config.ApplicationName,
-		InitialCap:       config.ConnectionInitNumber,
-		MaxIdle:          config.ConnectionMaxIdle,
-		MaxCap:           config.ConnectionMax,
-		Lifespan:         config.ConnectionLifespan,
-		IdleTimeout:      config.ConnectionIdleTimeout,
-		OperationTimeout: config.OperationTimeout,
-		TcpBufferSize:    config.TcpBufferSize,
+		Account:              &poolAccount,
+		ApplicationName:      config.ApplicationName,
+		InitialCap:           config.ConnectionInitNumber,
+		MaxIdle:              config.ConnectionMaxIdle,
+		MaxCap:               config.ConnectionMax,
+		Lifespan:             config.ConnectionLifespan,
+		IdleTimeout:          config.ConnectionIdleTimeout,
+		OperationTimeout:     config.OperationTimeout,
+		TcpBufferSize:        config.TcpBufferSize,
+		ReservedHighPriority: config.ConnectionReservedHighPriority,
+		TCPKeepAlive:         config.TCPKeepAlive,
+		HealthCheckInterval:  config.ConnectionHealthCheckInterval,
+		ValidateOnBorrow:     config.ConnectionValidateOnBorrow,
+		Dialer:               config.Dialer,
+
+		Endpoints:               endpoints,
+		EndpointSelectionPolicy: config.EndpointSelectionPolicy,
+		EndpointBackoff:         config.EndpointBackoff,
+	}
+
+	// pool and unmanaged connections use different accounts, share refreshed pam tokens
+	sess.pamTokenRefresher = NewPAMTokenRefresher(account, func(pamToken string) {
+		sess.connectionPool.pamTokenRefresher.SetPAMToken(pamToken)
+		sess.callPAMTokenRefreshHandler(pamToken)
+	})
+
+	poolConfig.PAMTokenRefreshHandler = func(pamToken string) {
+		sess.pamTokenRefresher.SetPAMToken(pamToken)
+		sess.callPAMTokenRefreshHandler(pamToken)
 	}
 
 	pool, err := NewConnectionPool(&poolConfig, &sess.metrics)
@@ -103,6 +130,13 @@ func NewIRODSSessionWithAddressResolver(account *types.IRODSAccount, config *IRO
 	return &sess, nil
 }
 
+// callPAMTokenRefreshHandler calls the pam token refresh handler if it is set
+func (sess *IRODSSession) callPAMTokenRefreshHandler(pamToken string) {
+	if sess.config.PAMTokenRefreshHandler != nil {
+		sess.config.PAMTokenRefreshHandler(pamToken)
+	}
+}
+
 // IsConnectionError returns if there is a failure
 func (sess *IRODSSession) GetLastConnectionError() (time.Time, error) {
 	sess.mutex.Lock()
@@ -222,62 +256,178 @@ func (sess *IRODSSession) endTransaction(conn *connection.IRODSConnection) error
 }
 
 // AcquireConnection returns an idle connection
+// if all connections are occupied, waits for ConnectionWaitTimeout, then shares an in-use connection
 func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, error) {
+	return sess.AcquireConnectionWithPriority(ConnectionPriorityHigh)
+}
+
+// AcquireConnectionWithPriority returns an idle connection
+// if all connections are occupied, waits in the queue of the priority for ConnectionWaitTimeout, then shares an in-use connection
+// use ConnectionPriorityLow for long-running operations, such as data transfers, so short operations are served first
+func (sess *IRODSSession) AcquireConnectionWithPriority(priority ConnectionPriority) (*connection.IRODSConnection, error) {
+	return sess.acquireConnectionWithTimeout(priority, sess.account.ClientUser, sess.account.ClientZone)
+}
+
+// acquireConnectionWithTimeout returns an idle connection acting for the client user, waits for ConnectionWaitTimeout, then shares an in-use connection
+func (sess *IRODSSession) acquireConnectionWithTimeout(priority ConnectionPriority, clientUser string, clientZone string) (*connection.IRODSConnection, error) {
+	if sess.config.ConnectionWaitTimeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), sess.config.ConnectionWaitTimeout)
+		defer cancel()
+
+		return sess.acquireConnection(ctx, priority, true, true, clientUser, clientZone)
+	}
+
+	return sess.acquireConnection(context.Background(), priority, false, true, clientUser, clientZone)
+}
+
+// AcquireConnectionWithContext returns an idle connection
+// if all connections are occupied, waits in the queue of the priority until a connection is returned or ctx is done
+// in-use connections are not shared, returns ConnectionPoolFullError if ctx is done before
+func (sess *IRODSSession) AcquireConnectionWithContext(ctx context.Context, priority ConnectionPriority) (*connection.IRODSConnection, error) {
+	return sess.acquireConnection(ctx, priority, true, false, sess.account.ClientUser, sess.account.ClientZone)
+}
+
+// AcquireConnectionAs returns an idle connection acting for the client user
+// the proxy user of the account must be a rodsadmin, requires iRODS 4.3.1 or higher
+// in-use connections are shared only if they act for the same client user
+func (sess *IRODSSession) AcquireConnectionAs(clientUser string, clientZone string) (*connection.IRODSConnection, error) {
+	return sess.acquireConnectionWithTimeout(ConnectionPriorityHigh, clientUser, clientZone)
+}
+
+// AcquireConnectionAsWithContext returns an idle connection acting for the client user
+// waits like AcquireConnectionWithContext, in-use connections are not shared
+func (sess *IRODSSession) AcquireConnectionAsWithContext(ctx context.Context, priority ConnectionPriority, clientUser string, clientZone string) (*connection.IRODSConnection, error) {
+	return sess.acquireConnection(ctx, priority, true, false, clientUser, clientZone)
+}
+
+// switchUser switches the client user of the connection acquired from the pool
+// connections keep the client user after returned, so it is switched only when it differs
+// the connection is discarded if switching fails, as the server may have closed its state
+func (sess *IRODSSession) switchUser(conn *connection.IRODSConnection, clientUser string, clientZone string) error {
 	logger := log.WithFields(log.Fields{
 		"package":  "session",
 		"struct":   "IRODSSession",
-		"function": "AcquireConnection",
+		"function": "switchUser",
 	})
 
-	sess.mutex.Lock()
-	defer sess.mutex.Unlock()
+	if conn.GetClientUser() == clientUser && conn.GetClientZone() == clientZone {
+		return nil
+	}
+
+	if !conn.SupportSwitchUser() {
+		sess.connectionPool.Return(conn)
+		return xerrors.Errorf("failed to switch client user to %s#%s, does not support switching user in current iRODS Version", clientUser, clientZone)
+	}
+
+	logger.Debugf("Switching client user of a connection from %s#%s to %s#%s", conn.GetClientUser(), conn.GetClientZone(), clientUser, clientZone)
+
+	conn.Lock()
+	err := conn.SwitchUser(clientUser, clientZone)
+	conn.Unlock()
 
+	if err != nil {
+		sess.connectionPool.Discard(conn)
+		return xerrors.Errorf("failed to switch client user to %s#%s: %w", clientUser, clientZone, err)
+	}
+	return nil
+}
+
+// returnConnectionsToPool returns connections not handed out yet to the pool
+func (sess *IRODSSession) returnConnectionsToPool(connections []*connection.IRODSConnection) {
+	for _, conn := range connections {
+		sess.connectionPool.Return(conn)
+	}
+}
+
+// acquireConnection returns an idle connection acting for the client user
+// the session lock is not held while waiting for the pool, so returning connections is not blocked
+func (sess *IRODSSession) acquireConnection(ctx context.Context, priority ConnectionPriority, wait bool, share bool, clientUser string, clientZone string) (*connection.IRODSConnection, error) {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "IRODSSession",
+		"function": "acquireConnection",
+	})
+
+	sess.mutex.Lock()
 	// return last error
 	pendingErr := sess.getPendingError()
+	sess.mutex.Unlock()
+
 	if pendingErr != nil {
 		return nil, xerrors.Errorf("failed to get a connection from the pool because pending error is found: %w", pendingErr)
 	}
 
-	// check if there are available connections in the pool
-	if sess.connectionPool.AvailableConnections() > 0 {
-		// try to get it from the pool
-		conn, _, err := sess.connectionPool.Get()
-		// ignore error this happens when connections in the pool are all occupied
+	var conn *connection.IRODSConnection
+	var err error
+	if wait {
+		conn, _, err = sess.connectionPool.GetWithContext(ctx, priority)
+	} else {
+		conn, _, err = sess.connectionPool.GetWithPriority(priority)
+	}
+
+	if err == nil {
+		err = sess.switchUser(conn, clientUser, clientZone)
 		if err != nil {
-			if types.IsConnectionPoolFullError(err) {
-				logger.WithError(err).Debug("failed to get a connection from the pool, the pool is full")
-				// fall below
-			} else {
-				// fail
-				sess.lastConnectionError = err
-				sess.lastConnectionErrorTime = time.Now()
+			return nil, err
+		}
+	}
 
-				return nil, err
-			}
-		} else {
-			// put to share
-			if shares, ok := sess.sharedConnections[conn]; ok {
-				shares++
-				sess.sharedConnections[conn] = shares
-			} else {
-				sess.sharedConnections[conn] = 1
-			}
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
 
-			if !sess.supportParallelUploadSet {
-				sess.supportParallelUpload = conn.SupportParallelUpload()
-				sess.supportParallelUploadSet = true
-			}
+	if err != nil {
+		if !types.IsConnectionPoolFullError(err) {
+			// fail
+			sess.lastConnectionError = err
+			sess.lastConnectionErrorTime = time.Now()
 
-			return conn, nil
+			return nil, err
 		}
+
+		if !share {
+			return nil, err
+		}
+
+		// ignore error this happens when connections in the pool are all occupied
+		logger.WithError(err).Debug("failed to get a connection from the pool, the pool is full")
+		return sess.shareConnection(clientUser, clientZone)
 	}
 
+	// put to share
+	if shares, ok := sess.sharedConnections[conn]; ok {
+		shares++
+		sess.sharedConnections[conn] = shares
+	} else {
+		sess.sharedConnections[conn] = 1
+	}
+
+	if !sess.supportParallelUploadSet {
+		sess.supportParallelUpload = conn.SupportParallelUpload()
+		sess.supportParallelUploadSet = true
+	}
+
+	return conn, nil
+}
+
+// shareConnection returns an in-use connection acting for the client user that has minimum share count
+func (sess *IRODSSession) shareConnection(clientUser string, clientZone string) (*connection.IRODSConnection, error) {
+	logger := log.WithFields(log.Fields{
+		"package":  "session",
+		"struct":   "IRODSSession",
+		"function": "shareConnection",
+	})
+
 	// failed to get connection from pool
 	// find a connection from shared connection list that has minimum share count
 	logger.Debug("Share an in-use connection as it cannot create a new connection")
 	minShare := 0
 	var minShareConn *connection.IRODSConnection
 	for sharedConn, shareCount := range sess.sharedConnections {
+		if sharedConn.GetClientUser() != clientUser || sharedConn.GetClientZone() != clientZone {
+			// acting for other user
+			continue
+		}
+
 		if minShare == 0 || shareCount < minShare {
 			minShare = shareCount
 			minShareConn = sharedConn
@@ -302,6 +452,7 @@ func (sess *IRODSSession) AcquireConnection() (*connection.IRODSConnection, erro
 }
 
 // AcquireConnectionsMulti returns idle connections
+// connections are for parallel data transfers, taken with low priority
 func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRODSConnection, error) {
 	logger := log.WithFields(log.Fields{
 		"package":  "session",
@@ -310,46 +461,70 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 	})
 
 	sess.mutex.Lock()
-	defer sess.mutex.Unlock()
-
 	// return last error
 	pendingErr := sess.getPendingError()
+	sess.mutex.Unlock()
+
 	if pendingErr != nil {
 		return nil, xerrors.Errorf("failed to get a connection from the pool because pending error is found: %w", pendingErr)
 	}
 
-	connections := map[*connection.IRODSConnection]bool{}
+	newConnections := []*connection.IRODSConnection{}
 
 	// check if there are available connections in the pool
 	for i := 0; i < number; i++ {
-		if sess.connectionPool.AvailableConnections() > 0 {
-			// try to get it from the pool
-			conn, _, err := sess.connectionPool.Get()
-			if err != nil {
-				if types.IsConnectionPoolFullError(err) {
-					logger.WithError(err).Debug("failed to get a connection from the pool, the pool is full")
-					// fall below
-				} else {
-					// fail
-					sess.lastConnectionError = err
-					sess.lastConnectionErrorTime = time.Now()
-
-					return nil, err
-				}
+		if sess.connectionPool.AvailableConnections() <= 0 {
+			break
+		}
+
+		// try to get it from the pool
+		conn, _, err := sess.connectionPool.GetWithPriority(ConnectionPriorityLow)
+		if err != nil {
+			if types.IsConnectionPoolFullError(err) {
+				logger.WithError(err).Debug("failed to get a connection from the pool, the pool is full")
+				// fall below
 				break
-			} else {
-				connections[conn] = true
-
-				// put to share
-				if shares, ok := sess.sharedConnections[conn]; ok {
-					shares++
-					sess.sharedConnections[conn] = shares
-				} else {
-					sess.sharedConnections[conn] = 1
-				}
 			}
+
+			// fail
+			sess.returnConnectionsToPool(newConnections)
+
+			sess.mutex.Lock()
+			sess.lastConnectionError = err
+			sess.lastConnectionErrorTime = time.Now()
+			sess.mutex.Unlock()
+
+			return nil, err
+		}
+
+		newConnections = append(newConnections, conn)
+	}
+
+	// switch user without holding the session lock
+	for idx, conn := range newConnections {
+		err := sess.switchUser(conn, sess.account.ClientUser, sess.account.ClientZone)
+		if err != nil {
+			// the connection failed is returned or discarded already
+			sess.returnConnectionsToPool(newConnections[:idx])
+			sess.returnConnectionsToPool(newConnections[idx+1:])
+			return nil, err
+		}
+	}
+
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	connections := map[*connection.IRODSConnection]bool{}
+
+	for _, conn := range newConnections {
+		connections[conn] = true
+
+		// put to share
+		if shares, ok := sess.sharedConnections[conn]; ok {
+			shares++
+			sess.sharedConnections[conn] = shares
 		} else {
-			break
+			sess.sharedConnections[conn] = 1
 		}
 	}
 
@@ -359,7 +534,14 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 	// find a connection from shared connection
 	logger.Debug("Share an in-use connection as it cannot create a new connection")
 	for connectionsInNeed > 0 {
+		shared := false
 		for sharedConn, shareCount := range sess.sharedConnections {
+			if sharedConn.GetClientUser() != sess.account.ClientUser || sharedConn.GetClientZone() != sess.account.ClientZone {
+				// acting for other user
+				continue
+			}
+
+			shared = true
 			shareCount++
 
 			connections[sharedConn] = true
@@ -370,6 +552,11 @@ func (sess *IRODSSession) AcquireConnectionsMulti(number int) ([]*connection.IRO
 				break
 			}
 		}
+
+		if !shared {
+			// no connections to share
+			break
+		}
 	}
 
 	acquiredConnections := []*connection.IRODSConnection{}
@@ -406,7 +593,11 @@ func (sess *IRODSSession) AcquireUnmanagedConnection() (*connection.IRODSConnect
 
 	// create a new one
 	newConn := connection.NewIRODSConnection(sess.account, sess.config.OperationTimeout, sess.config.ApplicationName)
-	err := newConn.Connect()
+	newConn.SetDialer(sess.config.Dialer)
+	// fails over to other catalog providers like pooled connections
+	err := sess.connectionPool.endpointSelector.connect(newConn, func() error {
+		return sess.pamTokenRefresher.Connect(newConn)
+	})
 	if err != nil {
 		sess.lastConnectionError = err
 		sess.lastConnectionErrorTime = time.Now()
@@ -566,6 +757,11 @@ func (sess *IRODSSession) ConnectionTotal() int {
 	return sess.connectionPool.OpenConnections()
 }
 
+// GetEndpointStatus returns health of catalog provider endpoints
+func (sess *IRODSSession) GetEndpointStatus() []EndpointStatus {
+	return sess.connectionPool.GetEndpointStatus()
+}
+
 // GetMetrics returns metrics
 func (sess *IRODSSession) GetMetrics() *metrics.IRODSMetrics {
 	return &sess.metrics
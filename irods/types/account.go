@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"regexp"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
@@ -33,6 +34,9 @@ type IRODSAccount struct {
 	SSLConfiguration        *IRODSSSLConfig
 	ServerNameTLS           string // Optional TLS Server Name for SNI connection and TLS verification - defaults to Host
 	SkipVerifyTLS           bool   // Skip TLS verification
+	// AdditionalEndpoints are other catalog providers of the zone, connections fail over to them
+	// Port 0 uses Port of the account
+	AdditionalEndpoints []IRODSEndpoint
 }
 
 // CreateIRODSAccount creates IRODSAccount
@@ -159,6 +163,24 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 		port = val.(int)
 	}
 
+	additionalEndpoints := []IRODSEndpoint{}
+	if val, ok := host["additional_hosts"]; ok {
+		for _, additionalHostObj := range val.([]interface{}) {
+			additionalHost := additionalHostObj.(map[string]interface{})
+
+			endpoint := IRODSEndpoint{}
+			if val, ok := additionalHost["hostname"]; ok {
+				endpoint.Host = val.(string)
+			}
+
+			if val, ok := additionalHost["port"]; ok {
+				endpoint.Port = val.(int)
+			}
+
+			additionalEndpoints = append(additionalEndpoints, endpoint)
+		}
+	}
+
 	defaultResource := ""
 	if val, ok := y["default_resource"]; ok {
 		defaultResource = val.(string)
@@ -289,12 +311,54 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 		hashRounds = val.(int)
 	}
 
+	certFile := ""
+	if val, ok := sslConfig["cert_file"]; ok {
+		certFile = val.(string)
+	}
+
+	keyFile := ""
+	if val, ok := sslConfig["key_file"]; ok {
+		keyFile = val.(string)
+	}
+
+	var minTLSVersion uint16 = 0
+	if val, ok := sslConfig["min_tls_version"]; ok {
+		minTLSVersion, err = GetTLSVersion(fmt.Sprintf("%v", val))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse min tls version: %w", err)
+		}
+	}
+
+	cipherSuites := []uint16{}
+	if val, ok := sslConfig["cipher_suites"]; ok {
+		for _, name := range val.([]interface{}) {
+			cipherSuite, err := GetCipherSuite(name.(string))
+			if err != nil {
+				return nil, xerrors.Errorf("failed to parse cipher suites: %w", err)
+			}
+			cipherSuites = append(cipherSuites, cipherSuite)
+		}
+	}
+
+	pinnedPublicKeys := []string{}
+	if val, ok := sslConfig["pinned_public_keys"]; ok {
+		for _, pin := range val.([]interface{}) {
+			pinnedPublicKeys = append(pinnedPublicKeys, pin.(string))
+		}
+	}
+
 	var irodsSSLConfig *IRODSSSLConfig = nil
 	if hasSSLConfig {
 		irodsSSLConfig, err = CreateIRODSSSLConfig(caCertFile, caCertPath, keySize, algorithm, saltSize, hashRounds)
 		if err != nil {
 			return nil, xerrors.Errorf("failed to create irods ssl config: %w", err)
 		}
+
+		irodsSSLConfig.CertificateChainFile = certFile
+		irodsSSLConfig.CertificateKeyFile = keyFile
+		irodsSSLConfig.MinTLSVersion = minTLSVersion
+		irodsSSLConfig.CipherSuites = cipherSuites
+		irodsSSLConfig.PinnedPublicKeys = pinnedPublicKeys
 	}
 
 	account := &IRODSAccount{
@@ -313,6 +377,7 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 		PamTTL:                  pamTTL,
 		PamToken:                pamToken,
 		SSLConfiguration:        irodsSSLConfig,
+		AdditionalEndpoints:     additionalEndpoints,
 	}
 
 	account.FixAuthConfiguration()
@@ -320,6 +385,37 @@ func CreateIRODSAccountFromYAML(yamlBytes []byte) (*IRODSAccount, error) {
 	return account, nil
 }
 
+// GetEndpoints returns endpoints of catalog providers, Host and Port of the account come first
+func (account *IRODSAccount) GetEndpoints() []IRODSEndpoint {
+	endpoints := []IRODSEndpoint{
+		{
+			Host: account.Host,
+			Port: account.Port,
+		},
+	}
+
+	for _, additionalEndpoint := range account.AdditionalEndpoints {
+		endpoint := additionalEndpoint
+		if endpoint.Port <= 0 {
+			endpoint.Port = account.Port
+		}
+
+		duplicated := false
+		for _, existingEndpoint := range endpoints {
+			if existingEndpoint == endpoint {
+				duplicated = true
+				break
+			}
+		}
+
+		if !duplicated {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	return endpoints
+}
+
 // SetSSLConfiguration sets SSL Configuration
 func (account *IRODSAccount) SetSSLConfiguration(sslConf *IRODSSSLConfig) {
 	account.SSLConfiguration = sslConf
@@ -353,6 +449,12 @@ func (account *IRODSAccount) Validate() error {
 		return xerrors.Errorf("empty port")
 	}
 
+	for _, endpoint := range account.AdditionalEndpoints {
+		if len(endpoint.Host) == 0 {
+			return xerrors.Errorf("empty host of additional endpoint")
+		}
+	}
+
 	if len(account.ProxyUser) == 0 {
 		return xerrors.Errorf("empty user")
 	}
@@ -435,3 +537,27 @@ func (account *IRODSAccount) GetRedacted() *IRODSAccount {
 
 	return &account2
 }
+
+// GetTemporaryAccount returns a copy of the account that logs in as the user with the temporary password
+// it uses native authentication and carries no long-lived credentials, such as password, pam token and ticket
+// if user is empty, the proxy user of the account is used
+func (account *IRODSAccount) GetTemporaryAccount(user string, temporaryPassword string) *IRODSAccount {
+	if len(user) == 0 {
+		user = account.ProxyUser
+	}
+
+	account2 := IRODSAccount{}
+	account2 = *account
+	account2.AuthenticationScheme = AuthSchemeNative
+	account2.ClientUser = user
+	account2.ClientZone = account.ProxyZone
+	account2.ProxyUser = user
+	account2.ProxyZone = account.ProxyZone
+	account2.Password = temporaryPassword
+	account2.Ticket = ""
+	account2.PamToken = ""
+
+	account2.FixAuthConfiguration()
+
+	return &account2
+}
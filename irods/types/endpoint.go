@@ -0,0 +1,21 @@
+package types
+
+import (
+	"fmt"
+)
+
+// IRODSEndpoint is a host and port of iRODS catalog provider
+type IRODSEndpoint struct {
+	Host string
+	Port int
+}
+
+// GetAddress returns the address in host:port form
+func (endpoint *IRODSEndpoint) GetAddress() string {
+	return fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+}
+
+// ToString stringifies the object
+func (endpoint *IRODSEndpoint) ToString() string {
+	return fmt.Sprintf("<IRODSEndpoint %s %d>", endpoint.Host, endpoint.Port)
+}
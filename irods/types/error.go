@@ -87,10 +87,12 @@ func (err *ConnectionError) Error() string {
 	return "connection error"
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrTransientNetwork category
 func (err *ConnectionError) Is(other error) bool {
-	_, ok := other.(*ConnectionError)
-	return ok
+	if _, ok := other.(*ConnectionError); ok {
+		return true
+	}
+	return errors.Is(ErrTransientNetwork, other)
 }
 
 // ToString stringifies the object
@@ -120,10 +122,12 @@ func (err *AuthError) Error() string {
 	return fmt.Sprintf("authentication error (auth scheme: '%s', username: '%s', zone: '%s')", err.Config.AuthenticationScheme, err.Config.ClientUser, err.Config.ClientZone)
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrAuthFailed category
 func (err *AuthError) Is(other error) bool {
-	_, ok := other.(*AuthError)
-	return ok
+	if _, ok := other.(*AuthError); ok {
+		return true
+	}
+	return errors.Is(ErrAuthFailed, other)
 }
 
 // ToString stringifies the object
@@ -146,7 +150,7 @@ type ConnectionPoolFullError struct {
 func NewConnectionPoolFullError(requested int, max int) error {
 	return &ConnectionPoolFullError{
 		Occupied: requested,
-		Max:      requested,
+		Max:      max,
 	}
 }
 
@@ -188,10 +192,12 @@ func (err *CollectionNotEmptyError) Error() string {
 	return fmt.Sprintf("collection not empty for path %s", err.Path)
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrNotEmpty category
 func (err *CollectionNotEmptyError) Is(other error) bool {
-	_, ok := other.(*CollectionNotEmptyError)
-	return ok
+	if _, ok := other.(*CollectionNotEmptyError); ok {
+		return true
+	}
+	return errors.Is(ErrNotEmpty, other)
 }
 
 // ToString stringifies the object
@@ -221,10 +227,12 @@ func (err *FileNotFoundError) Error() string {
 	return fmt.Sprintf("data object/collection not found for path %s", err.Path)
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrNotFound category
 func (err *FileNotFoundError) Is(other error) bool {
-	_, ok := other.(*FileNotFoundError)
-	return ok
+	if _, ok := other.(*FileNotFoundError); ok {
+		return true
+	}
+	return errors.Is(ErrNotFound, other)
 }
 
 // ToString stringifies the object
@@ -254,10 +262,12 @@ func (err *FileAlreadyExistError) Error() string {
 	return fmt.Sprintf("data object/collection already exist for path %s", err.Path)
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrAlreadyExists category
 func (err *FileAlreadyExistError) Is(other error) bool {
-	_, ok := other.(*FileAlreadyExistError)
-	return ok
+	if _, ok := other.(*FileAlreadyExistError); ok {
+		return true
+	}
+	return errors.Is(ErrAlreadyExists, other)
 }
 
 // ToString stringifies the object
@@ -287,10 +297,12 @@ func (err *TicketNotFoundError) Error() string {
 	return fmt.Sprintf("ticket %s not found", err.Ticket)
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrNotFound category
 func (err *TicketNotFoundError) Is(other error) bool {
-	_, ok := other.(*TicketNotFoundError)
-	return ok
+	if _, ok := other.(*TicketNotFoundError); ok {
+		return true
+	}
+	return errors.Is(ErrNotFound, other)
 }
 
 // ToString stringifies the object
@@ -320,10 +332,12 @@ func (err *UserNotFoundError) Error() string {
 	return fmt.Sprintf("user/group %s not found", err.Name)
 }
 
-// Is tests type of error
+// Is tests type of error, also matches ErrNotFound category
 func (err *UserNotFoundError) Is(other error) bool {
-	_, ok := other.(*UserNotFoundError)
-	return ok
+	if _, ok := other.(*UserNotFoundError); ok {
+		return true
+	}
+	return errors.Is(ErrNotFound, other)
 }
 
 // ToString stringifies the object
@@ -369,10 +383,17 @@ func (err *IRODSError) Error() string {
 	return err.Message
 }
 
-// Is tests type of error
+// Is tests type of error, also matches category of the error code
 func (err *IRODSError) Is(other error) bool {
-	_, ok := other.(*IRODSError)
-	return ok
+	if _, ok := other.(*IRODSError); ok {
+		return true
+	}
+
+	category := GetIRODSErrorCategory(err.Code)
+	if category == nil {
+		return false
+	}
+	return errors.Is(category, other)
 }
 
 // GetCode returns error code
@@ -0,0 +1,220 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+)
+
+// ErrorCategory is a category of errors, can be tested with errors.Is
+// categories for file errors wrap corresponding os errors, so errors.Is(err, os.ErrNotExist) works
+type ErrorCategory struct {
+	Name      string
+	Base      error
+	Retryable bool
+}
+
+// Error returns error message
+func (category *ErrorCategory) Error() string {
+	return category.Name
+}
+
+// Unwrap returns os error that the category corresponds to
+func (category *ErrorCategory) Unwrap() error {
+	return category.Base
+}
+
+// ToString stringifies the object
+func (category *ErrorCategory) ToString() string {
+	return fmt.Sprintf("<ErrorCategory %s>", category.Name)
+}
+
+var (
+	// ErrNotFound is a category for data object/collection/user/resource not found
+	ErrNotFound = &ErrorCategory{Name: "not found", Base: os.ErrNotExist, Retryable: false}
+	// ErrPermissionDenied is a category for access permission errors
+	ErrPermissionDenied = &ErrorCategory{Name: "permission denied", Base: os.ErrPermission, Retryable: false}
+	// ErrAlreadyExists is a category for data object/collection already exist
+	ErrAlreadyExists = &ErrorCategory{Name: "already exists", Base: os.ErrExist, Retryable: false}
+	// ErrNotEmpty is a category for collection not empty
+	ErrNotEmpty = &ErrorCategory{Name: "not empty", Base: nil, Retryable: false}
+	// ErrQuotaExceeded is a category for quota exceeded
+	ErrQuotaExceeded = &ErrorCategory{Name: "quota exceeded", Base: nil, Retryable: false}
+	// ErrLocked is a category for data object locked or being written by others
+	ErrLocked = &ErrorCategory{Name: "locked", Base: nil, Retryable: true}
+	// ErrChecksumMismatch is a category for checksum mismatch
+	ErrChecksumMismatch = &ErrorCategory{Name: "checksum mismatch", Base: nil, Retryable: false}
+	// ErrAuthFailed is a category for authentication failure
+	ErrAuthFailed = &ErrorCategory{Name: "authentication failed", Base: nil, Retryable: false}
+	// ErrTransientNetwork is a category for transient network or server availability errors
+	ErrTransientNetwork = &ErrorCategory{Name: "transient network error", Base: nil, Retryable: true}
+)
+
+var (
+	errorCategories = []*ErrorCategory{
+		ErrNotFound,
+		ErrPermissionDenied,
+		ErrAlreadyExists,
+		ErrNotEmpty,
+		ErrQuotaExceeded,
+		ErrLocked,
+		ErrChecksumMismatch,
+		ErrAuthFailed,
+		ErrTransientNetwork,
+	}
+
+	errorCodeCategoryTable = map[common.ErrorCode]*ErrorCategory{
+		// not found
+		common.CAT_NO_ROWS_FOUND:           ErrNotFound,
+		common.CAT_UNKNOWN_COLLECTION:      ErrNotFound,
+		common.CAT_UNKNOWN_FILE:            ErrNotFound,
+		common.CAT_INVALID_USER:            ErrNotFound,
+		common.CAT_INVALID_ZONE:            ErrNotFound,
+		common.CAT_INVALID_RESOURCE:        ErrNotFound,
+		common.USER_FILE_DOES_NOT_EXIST:    ErrNotFound,
+		common.OBJ_PATH_DOES_NOT_EXIST:     ErrNotFound,
+		common.SYS_RESC_DOES_NOT_EXIST:     ErrNotFound,
+		common.SYS_REPLICA_DOES_NOT_EXIST:  ErrNotFound,
+		common.SYS_SPEC_COLL_OBJ_NOT_EXIST: ErrNotFound,
+		common.SYS_COPY_NOT_EXIST_IN_RESC:  ErrNotFound,
+
+		// permission denied
+		common.CAT_NO_ACCESS_PERMISSION:         ErrPermissionDenied,
+		common.CAT_INSUFFICIENT_PRIVILEGE_LEVEL: ErrPermissionDenied,
+		common.CAT_TABLE_ACCESS_DENIED:          ErrPermissionDenied,
+		common.SYS_NO_API_PRIV:                  ErrPermissionDenied,
+		common.SYS_NO_PATH_PERMISSION:           ErrPermissionDenied,
+		common.SYS_PROXYUSER_NO_PRIV:            ErrPermissionDenied,
+		common.SYS_NO_DATA_OBJ_PERMISSION:       ErrPermissionDenied,
+		common.SYS_USER_NO_PERMISSION:           ErrPermissionDenied,
+		common.USER_ACCESS_DENIED:               ErrPermissionDenied,
+
+		// already exists
+		common.CATALOG_ALREADY_HAS_ITEM_BY_THAT_NAME: ErrAlreadyExists,
+		common.CAT_NAME_EXISTS_AS_COLLECTION:         ErrAlreadyExists,
+		common.CAT_NAME_EXISTS_AS_DATAOBJ:            ErrAlreadyExists,
+		common.OVERWRITE_WITHOUT_FORCE_FLAG:          ErrAlreadyExists,
+		common.SYS_COPY_ALREADY_IN_RESC:              ErrAlreadyExists,
+
+		// not empty
+		common.CAT_COLLECTION_NOT_EMPTY:   ErrNotEmpty,
+		common.CAT_RESOURCE_NOT_EMPTY:     ErrNotEmpty,
+		common.SYS_COLLECTION_NOT_EMPTY:   ErrNotEmpty,
+		common.SYS_DIR_IN_VAULT_NOT_EMPTY: ErrNotEmpty,
+
+		// quota exceeded
+		common.SYS_RESC_QUOTA_EXCEEDED: ErrQuotaExceeded,
+
+		// locked
+		common.LOCKED_DATA_OBJECT_ACCESS:   ErrLocked,
+		common.INTERMEDIATE_REPLICA_ACCESS: ErrLocked,
+
+		// checksum mismatch
+		common.USER_CHKSUM_MISMATCH: ErrChecksumMismatch,
+
+		// auth failed
+		common.CAT_INVALID_AUTHENTICATION:           ErrAuthFailed,
+		common.CAT_PASSWORD_EXPIRED:                 ErrAuthFailed,
+		common.PAM_AUTH_PASSWORD_FAILED:             ErrAuthFailed,
+		common.REMOTE_SERVER_AUTHENTICATION_FAILURE: ErrAuthFailed,
+		common.REMOTE_SERVER_AUTH_NOT_PROVIDED:      ErrAuthFailed,
+		common.REMOTE_SERVER_AUTH_EMPTY:             ErrAuthFailed,
+		common.USER_AUTH_SCHEME_ERR:                 ErrAuthFailed,
+		common.USER_AUTH_STRING_EMPTY:               ErrAuthFailed,
+
+		// transient network
+		common.SYS_SOCK_OPEN_ERR:              ErrTransientNetwork,
+		common.SYS_SOCK_ACCEPT_ERR:            ErrTransientNetwork,
+		common.SYS_SOCK_READ_TIMEDOUT:         ErrTransientNetwork,
+		common.SYS_SOCK_READ_ERR:              ErrTransientNetwork,
+		common.SYS_SOCK_SELECT_ERR:            ErrTransientNetwork,
+		common.SYS_SOCK_WRITE_ERR:             ErrTransientNetwork,
+		common.SYS_SOCK_CONNECT_ERR:           ErrTransientNetwork,
+		common.SYS_HEADER_READ_LEN_ERR:        ErrTransientNetwork,
+		common.SYS_HEADER_WRITE_LEN_ERR:       ErrTransientNetwork,
+		common.SYS_READ_MSG_BODY_INPUT_ERR:    ErrTransientNetwork,
+		common.SYS_READ_MSG_BODY_LEN_ERR:      ErrTransientNetwork,
+		common.SYS_SVR_TO_SVR_CONNECT_FAILED:  ErrTransientNetwork,
+		common.SYS_EXCEED_CONNECT_CNT:         ErrTransientNetwork,
+		common.SYS_MAX_CONNECT_COUNT_EXCEEDED: ErrTransientNetwork,
+		common.SYS_RESC_IS_DOWN:               ErrTransientNetwork,
+		common.USER_SOCK_OPEN_ERR:             ErrTransientNetwork,
+		common.USER_SOCK_CONNECT_ERR:          ErrTransientNetwork,
+		common.USER_SOCK_CONNECT_TIMEDOUT:     ErrTransientNetwork,
+		common.CROSS_ZONE_SOCK_CONNECT_ERR:    ErrTransientNetwork,
+		common.CATALOG_NOT_CONNECTED:          ErrTransientNetwork,
+		common.CAT_CONNECT_ERR:                ErrTransientNetwork,
+		common.REMOTE_IRODS_CONNECT_ERR:       ErrTransientNetwork,
+		common.UNIX_FILE_OPR_TIMEOUT_ERR:      ErrTransientNetwork,
+	}
+)
+
+// GetIRODSErrorCategory returns category of the given iRODS error code, nil if the code has no category
+// sub error codes (e.g., -818013) are categorized by their main error code
+func GetIRODSErrorCategory(code common.ErrorCode) *ErrorCategory {
+	if category, ok := errorCodeCategoryTable[code]; ok {
+		return category
+	}
+
+	mainErrCode, _ := common.SplitIRODSErrorCode(code)
+	if category, ok := errorCodeCategoryTable[mainErrCode]; ok {
+		return category
+	}
+
+	return nil
+}
+
+// GetErrorCategory returns category of the given error, nil if the error has no category
+func GetErrorCategory(err error) *ErrorCategory {
+	if err == nil {
+		return nil
+	}
+
+	for _, category := range errorCategories {
+		if errors.Is(err, category) {
+			return category
+		}
+	}
+
+	return nil
+}
+
+// IsRetryableErrorCode returns if the given iRODS error code is transient and the request can be retried
+func IsRetryableErrorCode(code common.ErrorCode) bool {
+	category := GetIRODSErrorCategory(code)
+	if category == nil {
+		return false
+	}
+
+	return category.Retryable
+}
+
+// IsRetryableError returns if the given error is transient and the request can be retried
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if IsPermanantFailure(err) {
+		return false
+	}
+
+	if IsConnectionError(err) || IsConnectionPoolFullError(err) {
+		return true
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return true
+	}
+
+	category := GetErrorCategory(err)
+	if category == nil {
+		return false
+	}
+
+	return category.Retryable
+}
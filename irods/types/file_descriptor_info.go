@@ -0,0 +1,33 @@
+package types
+
+import (
+	"fmt"
+)
+
+// IRODSFileDescriptorInfo contains information of a replica opened, returned by get file descriptor info API
+type IRODSFileDescriptorInfo struct {
+	FileDescriptor int
+	// Path has an absolute path to the data object
+	Path              string
+	DataID            int64
+	ReplicaNumber     int64
+	ResourceName      string
+	ResourceHierarchy string
+	PhysicalPath      string
+	// DataSize is the size of the replica recorded in the catalog when it is opened
+	// it is not updated by writes via the file descriptor until the replica is closed
+	DataSize int64
+	// BytesWritten is the number of bytes written via the file descriptor
+	// overwritten ranges are counted too, so DataSize + BytesWritten is not the current size of the replica
+	BytesWritten  int64
+	Checksum      *IRODSChecksum
+	ReplicaStatus int
+	OpenFlags     int
+	OperationType int
+	ReplicaToken  string
+}
+
+// ToString stringifies the object
+func (info *IRODSFileDescriptorInfo) ToString() string {
+	return fmt.Sprintf("<IRODSFileDescriptorInfo %d %s %d %s %s %d %d>", info.FileDescriptor, info.Path, info.ReplicaNumber, info.ResourceHierarchy, info.PhysicalPath, info.DataSize, info.BytesWritten)
+}
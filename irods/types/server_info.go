@@ -0,0 +1,32 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// IRODSServerType is a type of iRODS server
+type IRODSServerType string
+
+const (
+	// IRODSServerTypeProvider is for servers having the catalog
+	IRODSServerTypeProvider IRODSServerType = "provider"
+	// IRODSServerTypeConsumer is for servers without the catalog
+	IRODSServerTypeConsumer IRODSServerType = "consumer"
+)
+
+// IRODSServerInfo contains irods server information
+type IRODSServerInfo struct {
+	Type           IRODSServerType
+	ReleaseVersion string // e.g., "rods4.2.8"
+	APIVersion     string
+	ReconnectPort  int
+	ReconnectAddr  string
+	BootTime       time.Time
+	Zone           string // zone of the catalog server
+}
+
+// ToString stringifies the object
+func (info *IRODSServerInfo) ToString() string {
+	return fmt.Sprintf("<IRODSServerInfo %s %s %s %s:%d %s %s>", info.Type, info.ReleaseVersion, info.APIVersion, info.ReconnectAddr, info.ReconnectPort, info.BootTime, info.Zone)
+}
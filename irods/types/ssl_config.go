@@ -1,7 +1,11 @@
 package types
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
+	"strings"
 
 	"github.com/hashicorp/go-rootcerts"
 	"golang.org/x/xerrors"
@@ -15,6 +19,21 @@ type IRODSSSLConfig struct {
 	EncryptionAlgorithm string
 	SaltSize            int
 	HashRounds          int
+
+	// client certificate for mutual TLS, the key file can be omitted if the chain file contains the key
+	CertificateChainFile string
+	CertificateKeyFile   string
+	// MinTLSVersion is a minimum TLS version, such as tls.VersionTLS12, 0 uses the Go default
+	MinTLSVersion uint16
+	// CipherSuites is a list of enabled cipher suites for TLS 1.2 and lower, empty uses the Go default
+	CipherSuites []uint16
+	// PinnedPublicKeys is a list of base64 encoded SHA-256 hashes of server public keys (SPKI)
+	// if set, one of the server certificates must have a public key pinned
+	PinnedPublicKeys []string
+	// TLSConfig is a base TLS configuration, fields above are applied on top of it if set
+	TLSConfig *tls.Config
+	// GetClientCertificate is a callback returning client certificate when the server requests it
+	GetClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
 }
 
 // CreateIRODSSSLConfig creates IRODSSSLConfig
@@ -45,3 +64,136 @@ func (config *IRODSSSLConfig) LoadCACert() (*x509.CertPool, error) {
 
 	return certPool, nil
 }
+
+// GetTLSConfig returns a TLS configuration to connect to the server
+func (config *IRODSSSLConfig) GetTLSConfig(serverName string, skipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if config.TLSConfig != nil {
+		tlsConfig = config.TLSConfig.Clone()
+	}
+
+	if tlsConfig.RootCAs == nil && (config.TLSConfig == nil || len(config.CACertificateFile) > 0 || len(config.CACertificatePath) > 0) {
+		caCertPool, err := config.LoadCACert()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if len(tlsConfig.ServerName) == 0 {
+		tlsConfig.ServerName = serverName
+	}
+
+	if skipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if config.MinTLSVersion > 0 {
+		tlsConfig.MinVersion = config.MinTLSVersion
+	}
+
+	if len(config.CipherSuites) > 0 {
+		tlsConfig.CipherSuites = config.CipherSuites
+	}
+
+	if len(config.CertificateChainFile) > 0 {
+		keyFile := config.CertificateKeyFile
+		if len(keyFile) == 0 {
+			keyFile = config.CertificateChainFile
+		}
+
+		cert, err := tls.LoadX509KeyPair(config.CertificateChainFile, keyFile)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load client certificate %s: %w", config.CertificateChainFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.GetClientCertificate != nil {
+		tlsConfig.GetClientCertificate = config.GetClientCertificate
+	}
+
+	if len(config.PinnedPublicKeys) > 0 {
+		pins := map[string]bool{}
+		for _, pin := range config.PinnedPublicKeys {
+			pins[normalizePublicKeyPin(pin)] = true
+		}
+
+		verifyConnection := tlsConfig.VerifyConnection
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if verifyConnection != nil {
+				err := verifyConnection(state)
+				if err != nil {
+					return err
+				}
+			}
+
+			// only certificates in verified chains can be trusted, any certificate can be appended to those sent by the server
+			// use the leaf certificate if verification is skipped
+			for _, chain := range state.VerifiedChains {
+				for _, cert := range chain {
+					if pins[GetPublicKeyPin(cert)] {
+						return nil
+					}
+				}
+			}
+
+			if len(state.VerifiedChains) == 0 && len(state.PeerCertificates) > 0 {
+				if pins[GetPublicKeyPin(state.PeerCertificates[0])] {
+					return nil
+				}
+			}
+			return xerrors.Errorf("failed to find a server certificate having a pinned public key")
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// GetPublicKeyPin returns a base64 encoded SHA-256 hash of the certificate's public key (SPKI)
+func GetPublicKeyPin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// normalizePublicKeyPin removes a prefix of the pin, e.g., "sha256//" as curl accepts
+func normalizePublicKeyPin(pin string) string {
+	pin = strings.TrimSpace(pin)
+	pin = strings.TrimPrefix(pin, "sha256//")
+	pin = strings.TrimPrefix(pin, "sha256/")
+	return pin
+}
+
+// GetTLSVersion returns TLS version value from string, e.g., "1.2"
+func GetTLSVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "tls") {
+	case "":
+		return 0, nil
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, xerrors.Errorf("unknown TLS version %s", version)
+	}
+}
+
+// GetCipherSuite returns cipher suite value from its name, e.g., "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"
+func GetCipherSuite(name string) (uint16, error) {
+	name = strings.TrimSpace(name)
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	return 0, xerrors.Errorf("unknown cipher suite %s", name)
+}
@@ -119,3 +119,23 @@ func GetRelativeIRODSPath(base string, target string) (string, error) {
 	}
 	return filepath.ToSlash(rel), nil
 }
+
+// IsIRODSSubPath returns true if the target path is under the base collection path
+func IsIRODSSubPath(base string, target string) bool {
+	base = strings.TrimSuffix(base, "/")
+	return strings.HasPrefix(target, base+"/") && len(target) > len(base)+1
+}
+
+// MakeIRODSLikePattern escapes wildcards in the string for a GenQuery like condition
+// single quotes cannot be escaped in GenQuery conditions, so they are replaced with a single character wildcard,
+// the pattern may match more paths, filter results with the original string
+func MakeIRODSLikePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `'`, `_`)
+	return replacer.Replace(s)
+}
+
+// MakeIRODSSubPathLikePattern returns a GenQuery like pattern matching all paths under the collection path
+// the pattern may match more paths, filter results with IsIRODSSubPath
+func MakeIRODSSubPathLikePattern(p string) string {
+	return MakeIRODSLikePattern(strings.TrimSuffix(p, "/")) + "/%"
+}
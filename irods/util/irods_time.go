@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"strconv"
 	"time"
 
@@ -21,6 +22,16 @@ func GetIRODSDateTime(timestring string) (time.Time, error) {
 	return time.Unix(i64, 0), nil
 }
 
+// GetIRODSDateTimeString returns IRODS time string from time struct
+// the string is zero-padded to 11 digits as iRODS stores, so it can be compared with stored time strings in queries
+func GetIRODSDateTimeString(t time.Time) string {
+	if t.IsZero() {
+		return fmt.Sprintf("%011d", 0)
+	}
+
+	return fmt.Sprintf("%011d", t.Unix())
+}
+
 // GetIRODSDateTimeStringForTicket returns IRODS time string from time struct
 func GetIRODSDateTimeStringForTicket(t time.Time) string {
 	if t.IsZero() {
@@ -21,6 +21,7 @@ func TestAdmin(t *testing.T) {
 	t.Run("test ClientSignature", testClientSignature)
 
 	t.Run("test CreateAndRemoveUser", testCreateAndRemoveUser)
+	t.Run("test TemporaryPassword", testTemporaryPassword)
 }
 
 func testEncoderRing(t *testing.T) {
@@ -102,3 +103,49 @@ func testCreateAndRemoveUser(t *testing.T) {
 	assert.Error(t, err)
 	userConn.Disconnect()
 }
+
+func testTemporaryPassword(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	conn := connection.NewIRODSConnection(account, 300*time.Second, "go-irodsclient-test")
+	err := conn.Connect()
+	failError(t, err)
+	defer conn.Disconnect()
+
+	// for self
+	tempPassword, err := fs.GetTemporaryPassword(conn)
+	failError(t, err)
+	assert.Equal(t, 32, len(tempPassword))
+
+	tempAccount := account.GetTemporaryAccount("", tempPassword)
+	assert.Equal(t, account.ProxyUser, tempAccount.ClientUser)
+	assert.Equal(t, types.AuthSchemeNative, tempAccount.AuthenticationScheme)
+
+	tempConn := connection.NewIRODSConnection(tempAccount, 300*time.Second, "go-irodsclient-test")
+	err = tempConn.Connect()
+	failError(t, err)
+	tempConn.Disconnect()
+
+	// for other
+	testUsername := "test_temp_user"
+
+	err = fs.CreateUser(conn, testUsername, account.ClientZone, "rodsuser")
+	failError(t, err)
+
+	tempPassword, err = fs.GetTemporaryPasswordForOther(conn, testUsername)
+	failError(t, err)
+
+	tempAccount = account.GetTemporaryAccount(testUsername, tempPassword)
+	assert.Equal(t, testUsername, tempAccount.ClientUser)
+	assert.Equal(t, testUsername, tempAccount.ProxyUser)
+
+	tempConn = connection.NewIRODSConnection(tempAccount, 300*time.Second, "go-irodsclient-test")
+	err = tempConn.Connect()
+	failError(t, err)
+	tempConn.Disconnect()
+
+	err = fs.RemoveUser(conn, testUsername, account.ClientZone)
+	failError(t, err)
+}
@@ -0,0 +1,200 @@
+package testcases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/auth"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+)
+
+// fakeAuthPluginConnection mimics server-side native and pam_password auth plugins
+type fakeAuthPluginConnection struct {
+	account         *types.IRODSAccount
+	ssl             bool
+	password        string
+	pamPassword     string
+	generated       string
+	challenge       string
+	clientSignature string
+	authenticated   bool
+	operations      []string
+}
+
+func (conn *fakeAuthPluginConnection) GetAccount() *types.IRODSAccount {
+	return conn.account
+}
+
+func (conn *fakeAuthPluginConnection) IsSSL() bool {
+	return conn.ssl
+}
+
+func (conn *fakeAuthPluginConnection) SetClientSignature(signature string) {
+	conn.clientSignature = signature
+}
+
+func (conn *fakeAuthPluginConnection) RequestAuthentication(request auth.AuthPluginContext) (auth.AuthPluginContext, error) {
+	operation := request.GetString(auth.AuthNextOperationKey)
+	conn.operations = append(conn.operations, request.GetString(auth.AuthSchemeKey)+":"+operation)
+
+	response := request.Copy()
+
+	switch request.GetString(auth.AuthSchemeKey) {
+	case auth.NativeAuthScheme:
+		switch operation {
+		case auth.AuthAgentAuthRequest:
+			response[auth.AuthRequestResultKey] = conn.challenge
+		case auth.AuthAgentAuthResponse:
+			digest := request.GetString(auth.AuthDigestKey)
+			if digest != auth.GenerateAuthResponse([]byte(conn.challenge), conn.password) && digest != auth.GenerateAuthResponse([]byte(conn.challenge), conn.generated) {
+				return nil, xerrors.Errorf("wrong digest")
+			}
+			conn.authenticated = true
+		default:
+			return nil, xerrors.Errorf("unknown operation %s", operation)
+		}
+	case auth.PAMPasswordAuthScheme:
+		if operation != auth.AuthAgentAuthRequest {
+			return nil, xerrors.Errorf("unknown operation %s", operation)
+		}
+
+		// the server-side pam_password plugin reads the password from "a_pw"
+		if request.GetString("a_pw") != conn.pamPassword {
+			return nil, xerrors.Errorf("wrong pam password")
+		}
+		response[auth.AuthRequestResultKey] = conn.generated
+	default:
+		return nil, xerrors.Errorf("unknown scheme")
+	}
+
+	return response, nil
+}
+
+// customAuthPlugin completes without server requests
+type customAuthPlugin struct {
+	executed []string
+}
+
+func (plugin *customAuthPlugin) GetScheme() string {
+	return "custom_test"
+}
+
+func (plugin *customAuthPlugin) Execute(conn auth.AuthPluginConnection, operation string, request auth.AuthPluginContext) (auth.AuthPluginContext, error) {
+	plugin.executed = append(plugin.executed, operation)
+
+	response := request.Copy()
+	switch operation {
+	case auth.AuthClientStart:
+		response[auth.AuthNextOperationKey] = "custom_step"
+	case "custom_step":
+		response[auth.AuthNextOperationKey] = auth.AuthClientAuthFlowComplete
+	default:
+		return nil, xerrors.Errorf("unknown operation %s", operation)
+	}
+	return response, nil
+}
+
+func TestAuthPlugin(t *testing.T) {
+	t.Run("test NativeAuthPlugin", testNativeAuthPlugin)
+	t.Run("test PAMPasswordAuthPlugin", testPAMPasswordAuthPlugin)
+	t.Run("test CustomAuthPlugin", testCustomAuthPlugin)
+}
+
+func newFakeAuthPluginConnection(authScheme types.AuthScheme) *fakeAuthPluginConnection {
+	account, _ := types.CreateIRODSAccount("localhost", 1247, "test", "tempZone", authScheme, "test_password", "")
+
+	return &fakeAuthPluginConnection{
+		account:     account,
+		password:    "test_password",
+		pamPassword: "test_password",
+		generated:   "generated_password",
+		challenge:   strings.Repeat("c", 64),
+	}
+}
+
+func testNativeAuthPlugin(t *testing.T) {
+	conn := newFakeAuthPluginConnection(types.AuthSchemeNative)
+
+	plugin, err := auth.GetAuthPlugin(auth.GetAuthPluginScheme(types.AuthSchemeNative))
+	failError(t, err)
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	failError(t, err)
+
+	assert.True(t, conn.authenticated)
+	assert.Equal(t, []string{"native:auth_agent_auth_request", "native:auth_agent_auth_response"}, conn.operations)
+	assert.Equal(t, auth.GenerateClientSignature([]byte(conn.challenge)), conn.clientSignature)
+
+	// wrong password
+	conn = newFakeAuthPluginConnection(types.AuthSchemeNative)
+	conn.account.Password = "wrong_password"
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	assert.Error(t, err)
+	assert.False(t, conn.authenticated)
+
+	// short challenge
+	conn = newFakeAuthPluginConnection(types.AuthSchemeNative)
+	conn.challenge = "short"
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	assert.Error(t, err)
+}
+
+func testPAMPasswordAuthPlugin(t *testing.T) {
+	conn := newFakeAuthPluginConnection(types.AuthSchemePAM)
+	conn.ssl = true
+
+	plugin, err := auth.GetAuthPlugin(auth.GetAuthPluginScheme(types.AuthSchemePAM))
+	failError(t, err)
+	assert.Equal(t, auth.PAMPasswordAuthScheme, plugin.GetScheme())
+	assert.Equal(t, "a_pw", auth.AuthPasswordKey)
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	failError(t, err)
+
+	assert.True(t, conn.authenticated)
+	assert.Equal(t, conn.generated, conn.account.PamToken)
+	assert.Equal(t, []string{"pam_password:auth_agent_auth_request", "native:auth_agent_auth_request", "native:auth_agent_auth_response"}, conn.operations)
+
+	// reuse the token
+	conn.authenticated = false
+	conn.operations = []string{}
+	conn.pamPassword = "changed_password"
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	failError(t, err)
+
+	assert.True(t, conn.authenticated)
+	assert.Equal(t, []string{"native:auth_agent_auth_request", "native:auth_agent_auth_response"}, conn.operations)
+
+	// requires ssl
+	conn = newFakeAuthPluginConnection(types.AuthSchemePAM)
+
+	err = auth.Authenticate(conn, plugin, auth.AuthPluginContext{})
+	assert.Error(t, err)
+}
+
+func testCustomAuthPlugin(t *testing.T) {
+	plugin := &customAuthPlugin{}
+	auth.RegisterAuthPlugin(plugin)
+
+	registered, err := auth.GetAuthPlugin(auth.GetAuthPluginScheme(types.AuthScheme("custom_test")))
+	failError(t, err)
+
+	conn := newFakeAuthPluginConnection(types.AuthScheme("custom_test"))
+	err = auth.Authenticate(conn, registered, auth.AuthPluginContext{})
+	failError(t, err)
+
+	assert.Equal(t, []string{auth.AuthClientStart, "custom_step"}, plugin.executed)
+
+	_, err = auth.GetAuthPlugin("unknown_test")
+	assert.Error(t, err)
+
+	// connections fall back to legacy login for schemes without plugins, e.g., GSI
+	assert.True(t, auth.HasAuthPlugin(auth.GetAuthPluginScheme(types.AuthSchemeNative)))
+	assert.True(t, auth.HasAuthPlugin(auth.GetAuthPluginScheme(types.AuthSchemePAM)))
+	assert.False(t, auth.HasAuthPlugin(auth.GetAuthPluginScheme(types.AuthSchemeGSI)))
+}
@@ -0,0 +1,232 @@
+package testcases
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/message"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDataObjectServer serves read and seek requests of an opened data object
+// the read request of failRead-th (1-based) fails without moving the offset
+type fakeDataObjectServer struct {
+	content  []byte
+	offset   int64
+	reads    int
+	failRead int
+}
+
+// serve handles requests until the connection is closed
+func (server *fakeDataObjectServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		header, body, err := readFakeServerMessage(conn)
+		if err != nil {
+			return
+		}
+
+		request := message.IRODSMessageOpenedDataObjectRequest{}
+
+		switch common.APINumber(header.IntInfo) {
+		case common.DATA_OBJ_READ_AN:
+			err = xml.Unmarshal(body, &request)
+			if err != nil {
+				return
+			}
+
+			server.reads++
+			if server.reads == server.failRead {
+				writeFakeServerMessage(conn, nil, nil, int32(common.SYS_INTERNAL_ERR))
+				continue
+			}
+
+			end := server.offset + request.Size
+			if end > int64(len(server.content)) {
+				end = int64(len(server.content))
+			}
+
+			data := server.content[server.offset:end]
+			server.offset = end
+			writeFakeServerMessage(conn, nil, data, int32(len(data)))
+		case common.DATA_OBJ_LSEEK_AN:
+			err = xml.Unmarshal(body, &request)
+			if err != nil {
+				return
+			}
+
+			server.offset = request.Offset
+			writeFakeServerMessage(conn, []byte(fmt.Sprintf("<fileLseekOut_PI><offset>%d</offset></fileLseekOut_PI>", server.offset)), nil, 0)
+		default:
+			return
+		}
+	}
+}
+
+// readFakeServerMessage reads a request message, returns its header and message body
+func readFakeServerMessage(conn net.Conn) (*message.IRODSMessageHeader, []byte, error) {
+	headerLenBuffer := make([]byte, 4)
+	_, err := io.ReadFull(conn, headerLenBuffer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headerBuffer := make([]byte, binary.BigEndian.Uint32(headerLenBuffer))
+	_, err = io.ReadFull(conn, headerBuffer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := message.IRODSMessageHeader{}
+	err = header.FromBytes(headerBuffer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := make([]byte, header.MessageLen+header.ErrorLen+header.BsLen)
+	_, err = io.ReadFull(conn, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &header, body[:header.MessageLen], nil
+}
+
+// writeFakeServerMessage writes a reply message
+func writeFakeServerMessage(conn net.Conn, body []byte, bs []byte, intInfo int32) error {
+	header := message.MakeIRODSMessageHeader(message.RODS_MESSAGE_API_REPLY_TYPE, uint32(len(body)), 0, uint32(len(bs)), intInfo)
+	headerBytes, err := header.GetBytes()
+	if err != nil {
+		return err
+	}
+
+	headerLenBuffer := make([]byte, 4)
+	binary.BigEndian.PutUint32(headerLenBuffer, uint32(len(headerBytes)))
+
+	buffer := append(headerLenBuffer, headerBytes...)
+	buffer = append(buffer, body...)
+	buffer = append(buffer, bs...)
+
+	_, err = conn.Write(buffer)
+	return err
+}
+
+func TestDataObjectReadPipeline(t *testing.T) {
+	t.Run("test ReadPipeline", testReadPipeline)
+	t.Run("test ReadPipelineFailedBlock", testReadPipelineFailedBlock)
+}
+
+// newFakeDataObjectConnection creates a connection bound to a fake server serving the content
+func newFakeDataObjectConnection(t *testing.T, server *fakeDataObjectServer) *connection.IRODSConnection {
+	account, err := types.CreateIRODSAccount("irods.example.com", 1247, "test", "tempZone", types.AuthSchemeNative, "test_password", "")
+	failError(t, err)
+
+	client, serverConn := net.Pipe()
+	go server.serve(serverConn)
+	t.Cleanup(func() { client.Close() })
+
+	conn := connection.NewIRODSConnection(account, 5*time.Second, "go-irodsclient-test")
+	conn.RawBind(client)
+	return conn
+}
+
+// newFakeDataObjectContent creates content of the size having distinct bytes at each offset
+func newFakeDataObjectContent(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	return content
+}
+
+func testReadPipeline(t *testing.T) {
+	blockSize := 16
+	content := newFakeDataObjectContent(10*blockSize + 5)
+	server := &fakeDataObjectServer{
+		content: content,
+	}
+
+	conn := newFakeDataObjectConnection(t, server)
+	handle := &types.IRODSFileHandle{
+		FileDescriptor: 3,
+		Path:           "/tempZone/home/test/file",
+	}
+
+	pipeline, err := irods_fs.NewDataObjectReadPipeline(conn, handle, 0, blockSize, 4)
+	failError(t, err)
+
+	readData := []byte{}
+	for {
+		data, err := pipeline.Next()
+		if err == io.EOF {
+			break
+		}
+		failError(t, err)
+
+		readData = append(readData, data...)
+	}
+
+	assert.Equal(t, content, readData)
+
+	serverOffset, err := pipeline.Close()
+	failError(t, err)
+	assert.Equal(t, int64(len(content)), serverOffset)
+}
+
+func testReadPipelineFailedBlock(t *testing.T) {
+	blockSize := 16
+	content := newFakeDataObjectContent(10 * blockSize)
+	server := &fakeDataObjectServer{
+		content:  content,
+		failRead: 2,
+	}
+
+	conn := newFakeDataObjectConnection(t, server)
+	handle := &types.IRODSFileHandle{
+		FileDescriptor: 3,
+		Path:           "/tempZone/home/test/file",
+	}
+
+	pipeline, err := irods_fs.NewDataObjectReadPipeline(conn, handle, 0, blockSize, 4)
+	failError(t, err)
+
+	data, err := pipeline.Next()
+	failError(t, err)
+	assert.Equal(t, content[:blockSize], data)
+
+	// the second block fails, following blocks in the window are read by the server
+	_, err = pipeline.Next()
+	assert.Error(t, err)
+
+	serverOffset, err := pipeline.Close()
+	assert.Error(t, err)
+
+	// blocks drained after the failure moved the server-side offset
+	assert.Equal(t, server.offset, serverOffset)
+	assert.Equal(t, int64(4*blockSize), serverOffset)
+
+	// the next read at the reported offset returns the bytes there
+	buffer := make([]byte, blockSize)
+	readLen, err := irods_fs.ReadDataObject(conn, handle, buffer)
+	failError(t, err)
+	assert.Equal(t, content[serverOffset:serverOffset+int64(readLen)], buffer[:readLen])
+
+	// the reader seeks back to its own offset before reading again
+	newOffset, err := irods_fs.SeekDataObject(conn, handle, int64(blockSize), types.SeekSet)
+	failError(t, err)
+	assert.Equal(t, int64(blockSize), newOffset)
+
+	readLen, err = irods_fs.ReadDataObject(conn, handle, buffer)
+	failError(t, err)
+	assert.Equal(t, content[blockSize:2*blockSize], buffer[:readLen])
+}
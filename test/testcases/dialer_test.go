@@ -0,0 +1,344 @@
+package testcases
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialer(t *testing.T) {
+	t.Run("test SOCKS5Dialer", testSOCKS5Dialer)
+	t.Run("test HTTPConnectDialer", testHTTPConnectDialer)
+	t.Run("test DialerFromEnvironment", testDialerFromEnvironment)
+	t.Run("test ConnectionDialer", testConnectionDialer)
+}
+
+// startEchoServer starts a server echoing back, returns its address
+func startEchoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	failError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// startFakeProxy starts a proxy handling connections with the handler, returns its address and a connection counter
+func startFakeProxy(t *testing.T, handler func(conn net.Conn) (string, error)) (string, *int32) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	failError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	var count int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			atomic.AddInt32(&count, 1)
+
+			go func() {
+				defer conn.Close()
+
+				target, err := handler(conn)
+				if err != nil {
+					return
+				}
+
+				targetConn, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer targetConn.Close()
+
+				go io.Copy(targetConn, conn)
+				io.Copy(conn, targetConn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), &count
+}
+
+// handleSOCKS5 handles socks5 handshake requiring username and password
+func handleSOCKS5(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+
+	conn.Write([]byte{0x05, 0x02})
+
+	// username and password
+	authHeader := make([]byte, 2)
+	if _, err := io.ReadFull(conn, authHeader); err != nil {
+		return "", err
+	}
+
+	username := make([]byte, authHeader[1])
+	io.ReadFull(conn, username)
+
+	passwordLen := make([]byte, 1)
+	io.ReadFull(conn, passwordLen)
+
+	password := make([]byte, passwordLen[0])
+	io.ReadFull(conn, password)
+
+	if string(username) != "proxy_user" || string(password) != "proxy_password" {
+		conn.Write([]byte{0x01, 0x01})
+		return "", fmt.Errorf("wrong password")
+	}
+	conn.Write([]byte{0x01, 0x00})
+
+	// connect request
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+
+	host := ""
+	switch request[3] {
+	case 0x01:
+		ip := make([]byte, 4)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 0x03:
+		hostLen := make([]byte, 1)
+		io.ReadFull(conn, hostLen)
+
+		hostBytes := make([]byte, hostLen[0])
+		io.ReadFull(conn, hostBytes)
+		host = string(hostBytes)
+	default:
+		return "", fmt.Errorf("unsupported address type")
+	}
+
+	port := make([]byte, 2)
+	io.ReadFull(conn, port)
+
+	conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// handleHTTPConnect handles HTTP CONNECT requiring basic auth
+func handleHTTPConnect(conn net.Conn) (string, error) {
+	request, err := http.ReadRequest(bufio.NewReader(conn))
+	if err != nil {
+		return "", err
+	}
+
+	if request.Method != http.MethodConnect {
+		conn.Write([]byte("HTTP/1.1 405 Method Not Allowed\r\n\r\n"))
+		return "", fmt.Errorf("not connect")
+	}
+
+	if request.Header.Get("Proxy-Authorization") != "Basic cHJveHlfdXNlcjpwcm94eV9wYXNzd29yZA==" {
+		conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+		return "", fmt.Errorf("wrong password")
+	}
+
+	conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	return request.Host, nil
+}
+
+// assertEcho checks the connection reaches the echo server
+func assertEcho(t *testing.T, conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err := conn.Write([]byte("hello"))
+	failError(t, err)
+
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	failError(t, err)
+	assert.Equal(t, "hello", string(buf))
+}
+
+func testSOCKS5Dialer(t *testing.T) {
+	echoAddress := startEchoServer(t)
+	proxyAddress, count := startFakeProxy(t, handleSOCKS5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, scheme := range []string{"socks5", "socks5h"} {
+		proxyURL, err := url.Parse(fmt.Sprintf("%s://proxy_user:proxy_password@%s", scheme, proxyAddress))
+		failError(t, err)
+
+		dialer, err := connection.NewProxyDialer(proxyURL, nil)
+		failError(t, err)
+
+		conn, err := dialer(ctx, "tcp", echoAddress)
+		failError(t, err)
+
+		assertEcho(t, conn)
+		conn.Close()
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+
+	// wrong password
+	proxyURL, err := url.Parse(fmt.Sprintf("socks5://proxy_user:wrong@%s", proxyAddress))
+	failError(t, err)
+
+	_, err = connection.NewSOCKS5Dialer(proxyURL, nil)(ctx, "tcp", echoAddress)
+	assert.Error(t, err)
+}
+
+func testHTTPConnectDialer(t *testing.T) {
+	echoAddress := startEchoServer(t)
+	proxyAddress, count := startFakeProxy(t, handleHTTPConnect)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	proxyURL, err := url.Parse(fmt.Sprintf("http://proxy_user:proxy_password@%s", proxyAddress))
+	failError(t, err)
+
+	dialer, err := connection.NewProxyDialer(proxyURL, nil)
+	failError(t, err)
+
+	conn, err := dialer(ctx, "tcp", echoAddress)
+	failError(t, err)
+
+	assertEcho(t, conn)
+	conn.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+
+	// no credentials
+	proxyURL, err = url.Parse(fmt.Sprintf("http://%s", proxyAddress))
+	failError(t, err)
+
+	_, err = connection.NewHTTPConnectDialer(proxyURL, nil)(ctx, "tcp", echoAddress)
+	assert.Error(t, err)
+}
+
+func testDialerFromEnvironment(t *testing.T) {
+	echoAddress := startEchoServer(t)
+	socksProxyAddress, socksCount := startFakeProxy(t, handleSOCKS5)
+	httpProxyAddress, httpCount := startFakeProxy(t, handleHTTPConnect)
+
+	for _, name := range []string{"ALL_PROXY", "all_proxy", "HTTPS_PROXY", "https_proxy", "NO_PROXY", "no_proxy"} {
+		t.Setenv(name, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// not set
+	dialer, err := connection.NewDialerFromEnvironment()
+	failError(t, err)
+	assert.Nil(t, dialer)
+
+	// HTTPS_PROXY without scheme
+	t.Setenv("HTTPS_PROXY", fmt.Sprintf("proxy_user:proxy_password@%s", httpProxyAddress))
+
+	dialer, err = connection.NewDialerFromEnvironment()
+	failError(t, err)
+
+	conn, err := dialer(ctx, "tcp", echoAddress)
+	failError(t, err)
+	assertEcho(t, conn)
+	conn.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(httpCount))
+
+	// ALL_PROXY takes precedence
+	t.Setenv("ALL_PROXY", fmt.Sprintf("socks5h://proxy_user:proxy_password@%s", socksProxyAddress))
+
+	dialer, err = connection.NewDialerFromEnvironment()
+	failError(t, err)
+
+	conn, err = dialer(ctx, "tcp", echoAddress)
+	failError(t, err)
+	assertEcho(t, conn)
+	conn.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(socksCount))
+	assert.Equal(t, int32(1), atomic.LoadInt32(httpCount))
+
+	// NO_PROXY
+	t.Setenv("NO_PROXY", "example.com, 127.0.0.0/8")
+
+	dialer, err = connection.NewDialerFromEnvironment()
+	failError(t, err)
+
+	conn, err = dialer(ctx, "tcp", echoAddress)
+	failError(t, err)
+	assertEcho(t, conn)
+	conn.Close()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(socksCount))
+
+	// unknown scheme
+	t.Setenv("ALL_PROXY", "ftp://127.0.0.1:21")
+
+	_, err = connection.NewDialerFromEnvironment()
+	assert.Error(t, err)
+}
+
+func testConnectionDialer(t *testing.T) {
+	account, err := types.CreateIRODSAccount("irods.example.com", 1247, "test", "tempZone", types.AuthSchemeNative, "test_password", "")
+	failError(t, err)
+
+	dialed := []string{}
+	dialer := func(ctx context.Context, network string, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		return nil, fmt.Errorf("blocked")
+	}
+
+	conn := connection.NewIRODSConnection(account, 10*time.Second, "go-irodsclient-test")
+	conn.SetDialer(dialer)
+
+	err = conn.Connect()
+	assert.Error(t, err)
+	assert.True(t, types.IsConnectionError(err))
+
+	// redirected connections use the dialer of the control connection
+	redirectionInfo := &types.IRODSRedirectionInfo{
+		Host:         "resource.example.com",
+		Port:         20000,
+		Cookie:       1,
+		ServerSocket: 1,
+	}
+
+	resourceConn := connection.NewIRODSResourceServerConnection(conn, redirectionInfo)
+	err = resourceConn.Connect()
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"irods.example.com:1247", "resource.example.com:20000"}, dialed)
+}
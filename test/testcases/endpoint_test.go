@@ -0,0 +1,96 @@
+package testcases
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoint(t *testing.T) {
+	t.Run("test EndpointsFromYAML", testEndpointsFromYAML)
+	t.Run("test EndpointsMarkedDown", testEndpointsMarkedDown)
+}
+
+// getClosedPort returns a local port no one listens on
+func getClosedPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	failError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	return port
+}
+
+func testEndpointsFromYAML(t *testing.T) {
+	yamlBytes := []byte(`
+host:
+  hostname: "provider1.example.com"
+  port: 1247
+  additional_hosts:
+    - hostname: "provider2.example.com"
+    - hostname: "provider3.example.com"
+      port: 1248
+    - hostname: "provider1.example.com"
+      port: 1247
+user:
+  username: "test"
+  password: "test_password"
+  zone: "tempZone"
+`)
+
+	account, err := types.CreateIRODSAccountFromYAML(yamlBytes)
+	failError(t, err)
+
+	failError(t, account.Validate())
+
+	endpoints := account.GetEndpoints()
+	assert.Equal(t, []types.IRODSEndpoint{
+		{Host: "provider1.example.com", Port: 1247},
+		{Host: "provider2.example.com", Port: 1247},
+		{Host: "provider3.example.com", Port: 1248},
+	}, endpoints)
+}
+
+func testEndpointsMarkedDown(t *testing.T) {
+	port1 := getClosedPort(t)
+	port2 := getClosedPort(t)
+
+	account, err := types.CreateIRODSAccount("127.0.0.1", port1, "test", "tempZone", types.AuthSchemeNative, "test_password", "")
+	failError(t, err)
+
+	account.AdditionalEndpoints = []types.IRODSEndpoint{
+		{Host: "127.0.0.1", Port: port2},
+	}
+
+	sessionConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessionConfig.EndpointSelectionPolicy = session.EndpointSelectionLeastConnections
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	for _, status := range sess.GetEndpointStatus() {
+		assert.True(t, status.Healthy)
+	}
+
+	// fails over to the second endpoint, then fails
+	_, err = sess.AcquireUnmanagedConnection()
+	assert.Error(t, err)
+	assert.True(t, types.IsConnectionError(err))
+
+	statuses := sess.GetEndpointStatus()
+	assert.Equal(t, 2, len(statuses))
+	for _, status := range statuses {
+		assert.False(t, status.Healthy)
+		assert.Equal(t, 1, status.Failures)
+		assert.Equal(t, 0, status.Connections)
+		assert.Error(t, status.LastError)
+		assert.True(t, status.DownUntil.After(status.LastErrorTime))
+	}
+
+	assert.Equal(t, port1, statuses[0].Endpoint.Port)
+	assert.Equal(t, port2, statuses[1].Endpoint.Port)
+}
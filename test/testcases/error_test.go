@@ -1,14 +1,19 @@
 package testcases
 
 import (
+	"errors"
+	"os"
 	"testing"
 
 	"github.com/cyverse/go-irodsclient/irods/common"
+	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
 )
 
 func TestError(t *testing.T) {
 	t.Run("test ErrorString", testErrorString)
+	t.Run("test ErrorCategory", testErrorCategory)
 }
 
 func testErrorString(t *testing.T) {
@@ -35,3 +40,45 @@ func testErrorString(t *testing.T) {
 	assert.Contains(t, errstr, "I/O error")
 
 }
+
+func testErrorCategory(t *testing.T) {
+	// not found
+	err := xerrors.Errorf("failed to stat: %w", types.NewIRODSError(common.CAT_NO_ROWS_FOUND))
+	assert.True(t, errors.Is(err, types.ErrNotFound))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.False(t, errors.Is(err, os.ErrPermission))
+	assert.True(t, types.IsIRODSError(err))
+	assert.Equal(t, types.ErrNotFound, types.GetErrorCategory(err))
+
+	err = xerrors.Errorf("failed to stat: %w", types.NewFileNotFoundError("/zone/home/test"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.True(t, types.IsFileNotFoundError(err))
+
+	// sub error code
+	err = types.NewIRODSError(common.ErrorCode(int(common.CAT_NO_ACCESS_PERMISSION) - int(common.EACCES)))
+	assert.True(t, errors.Is(err, types.ErrPermissionDenied))
+	assert.True(t, errors.Is(err, os.ErrPermission))
+
+	// already exists
+	err = types.NewIRODSError(common.CAT_NAME_EXISTS_AS_DATAOBJ)
+	assert.True(t, errors.Is(err, os.ErrExist))
+
+	// others
+	assert.True(t, errors.Is(types.NewIRODSError(common.CAT_COLLECTION_NOT_EMPTY), types.ErrNotEmpty))
+	assert.True(t, errors.Is(types.NewCollectionNotEmptyError("/zone/home/test"), types.ErrNotEmpty))
+	assert.True(t, errors.Is(types.NewIRODSError(common.SYS_RESC_QUOTA_EXCEEDED), types.ErrQuotaExceeded))
+	assert.True(t, errors.Is(types.NewIRODSError(common.LOCKED_DATA_OBJECT_ACCESS), types.ErrLocked))
+	assert.True(t, errors.Is(types.NewIRODSError(common.USER_CHKSUM_MISMATCH), types.ErrChecksumMismatch))
+	assert.True(t, errors.Is(types.NewIRODSError(common.CAT_INVALID_AUTHENTICATION), types.ErrAuthFailed))
+	assert.True(t, errors.Is(types.NewIRODSError(common.SYS_SOCK_READ_TIMEDOUT), types.ErrTransientNetwork))
+	assert.Nil(t, types.GetErrorCategory(types.NewIRODSError(common.SYS_INTERNAL_ERR)))
+
+	// retryable
+	assert.True(t, types.IsRetryableErrorCode(common.SYS_SOCK_READ_TIMEDOUT))
+	assert.True(t, types.IsRetryableErrorCode(common.LOCKED_DATA_OBJECT_ACCESS))
+	assert.False(t, types.IsRetryableErrorCode(common.CAT_NO_ROWS_FOUND))
+	assert.True(t, types.IsRetryableError(xerrors.Errorf("failed: %w", types.NewConnectionError())))
+	assert.True(t, types.IsRetryableError(types.NewIRODSError(common.SYS_HEADER_READ_LEN_ERR)))
+	assert.False(t, types.IsRetryableError(types.NewIRODSError(common.CAT_NO_ACCESS_PERMISSION)))
+	assert.False(t, types.IsRetryableError(nil))
+}
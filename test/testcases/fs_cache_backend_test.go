@@ -0,0 +1,174 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSCacheBackend(t *testing.T) {
+	t.Run("test CacheDiskBackend", testCacheDiskBackend)
+	t.Run("test CacheDiskBackendTimeout", testCacheDiskBackendTimeout)
+	t.Run("test CacheDiskBackendVersion", testCacheDiskBackendVersion)
+	t.Run("test CacheDiskBackendScope", testCacheDiskBackendScope)
+}
+
+func testCacheDiskBackend(t *testing.T) {
+	rootPath := t.TempDir()
+
+	backend, err := fs.NewFileSystemCacheDiskBackend(rootPath)
+	failError(t, err)
+
+	cache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, nil, true, backend)
+
+	entry := &fs.Entry{
+		ID:         200,
+		Type:       fs.FileEntry,
+		Name:       "file",
+		Path:       "/zone/home/test/file",
+		Size:       100,
+		ModifyTime: time.Now().UTC(),
+	}
+
+	metas := []*types.IRODSMeta{
+		{
+			Name:  "key",
+			Value: "value",
+			Units: "units",
+		},
+	}
+
+	accesses := []*types.IRODSAccess{
+		{
+			Path:        "/zone/home/test/file",
+			UserName:    "test",
+			UserZone:    "zone",
+			UserType:    types.IRODSUserRodsUser,
+			AccessLevel: types.IRODSAccessLevelOwner,
+		},
+	}
+
+	cache.AddEntryCache(entry)
+	cache.AddDirCache("/zone/home/test", []string{entry.Path})
+	cache.AddMetadataCache(entry.Path, metas)
+	cache.AddACLsCache(entry.Path, accesses)
+
+	// a new cache, as if the process is restarted
+	backend, err = fs.NewFileSystemCacheDiskBackend(rootPath)
+	failError(t, err)
+
+	newCache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, nil, true, backend)
+
+	cachedEntry := newCache.GetEntryCache(entry.Path)
+	assert.NotNil(t, cachedEntry)
+	assert.Equal(t, entry.ID, cachedEntry.ID)
+	assert.Equal(t, entry.Size, cachedEntry.Size)
+	assert.True(t, entry.ModifyTime.Equal(cachedEntry.ModifyTime))
+
+	assert.Equal(t, []string{entry.Path}, newCache.GetDirCache("/zone/home/test"))
+	assert.Equal(t, metas, newCache.GetMetadataCache(entry.Path))
+	assert.Equal(t, accesses, newCache.GetACLsCache(entry.Path))
+
+	// remove
+	newCache.RemoveEntryCache(entry.Path)
+	assert.Nil(t, newCache.GetEntryCache(entry.Path))
+	assert.Nil(t, cache.GetEntryCache("/zone/home/test/other"))
+
+	newCache.ClearDirCache()
+	assert.Nil(t, newCache.GetDirCache("/zone/home/test"))
+}
+
+func testCacheDiskBackendTimeout(t *testing.T) {
+	backend, err := fs.NewFileSystemCacheDiskBackend(t.TempDir())
+	failError(t, err)
+
+	timeoutSettings := []fs.MetadataCacheTimeoutSetting{
+		{
+			Path:    "/zone/home/test",
+			Timeout: 100 * time.Millisecond,
+			Inherit: true,
+		},
+	}
+
+	cache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, timeoutSettings, true, backend)
+
+	entry := &fs.Entry{
+		ID:   201,
+		Type: fs.FileEntry,
+		Name: "file",
+		Path: "/zone/home/test/file",
+	}
+
+	cache.AddEntryCache(entry)
+
+	newCache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, timeoutSettings, true, backend)
+	assert.NotNil(t, newCache.GetEntryCache(entry.Path))
+
+	time.Sleep(200 * time.Millisecond)
+
+	newCache = fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, timeoutSettings, true, backend)
+	assert.Nil(t, newCache.GetEntryCache(entry.Path))
+}
+
+func testCacheDiskBackendVersion(t *testing.T) {
+	backend, err := fs.NewFileSystemCacheDiskBackend(t.TempDir())
+	failError(t, err)
+
+	// written by an older version
+	err = backend.Set("entry", "/zone/home/test/file", []byte(`{"version":0,"key":"/zone/home/test/file","value":{"ID":202}}`))
+	failError(t, err)
+
+	cache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, nil, true, backend)
+	assert.Nil(t, cache.GetEntryCache("/zone/home/test/file"))
+
+	_, exist := backend.Get("entry", "/zone/home/test/file")
+	assert.False(t, exist)
+}
+
+func testCacheDiskBackendScope(t *testing.T) {
+	rootPath := t.TempDir()
+
+	backend, err := fs.NewFileSystemCacheDiskBackend(rootPath)
+	failError(t, err)
+
+	account, err := types.CreateIRODSAccount("localhost", 1247, "test", "zone", types.AuthSchemeNative, "test_password", "")
+	failError(t, err)
+
+	otherAccount, err := types.CreateIRODSProxyAccount("localhost", 1247, "other", "zone", "test", "zone", types.AuthSchemeNative, "test_password", "")
+	failError(t, err)
+
+	assert.NotEqual(t, fs.GetFileSystemCacheBackendScope(account), fs.GetFileSystemCacheBackendScope(otherAccount))
+
+	cache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, nil, true, backend)
+	cache.SetBackendScope(fs.GetFileSystemCacheBackendScope(account))
+
+	entry := &fs.Entry{
+		ID:   200,
+		Type: fs.FileEntry,
+		Name: "file",
+		Path: "/zone/home/test/file",
+		Size: 100,
+	}
+
+	cache.AddEntryCache(entry)
+
+	// other users do not see caches
+	otherCache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, nil, true, backend)
+	otherCache.SetBackendScope(fs.GetFileSystemCacheBackendScope(otherAccount))
+	assert.Nil(t, otherCache.GetEntryCache(entry.Path))
+
+	otherCache.ClearEntryCache()
+
+	// same user
+	sameCache := fs.NewFileSystemCacheWithBackend(time.Minute, time.Minute, nil, true, backend)
+	sameCache.SetBackendScope(fs.GetFileSystemCacheBackendScope(account))
+
+	cachedEntry := sameCache.GetEntryCache(entry.Path)
+	assert.NotNil(t, cachedEntry)
+	if cachedEntry != nil {
+		assert.Equal(t, entry.ID, cachedEntry.ID)
+	}
+}
@@ -0,0 +1,86 @@
+package testcases
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSCacheLimit(t *testing.T) {
+	t.Run("test CacheMaxEntries", testCacheMaxEntries)
+	t.Run("test CacheMaxBytes", testCacheMaxBytes)
+}
+
+func testCacheMaxEntries(t *testing.T) {
+	cache := fs.NewFileSystemCache(time.Minute, time.Minute, nil, true)
+	cache.SetCacheLimit(fs.FileSystemCacheNameEntry, fs.FileSystemCacheLimit{
+		MaxEntries: 2,
+	})
+
+	for i := 0; i < 3; i++ {
+		cache.AddEntryCache(&fs.Entry{
+			ID:   int64(300 + i),
+			Type: fs.FileEntry,
+			Name: fmt.Sprintf("file%d", i),
+			Path: fmt.Sprintf("/zone/home/test/file%d", i),
+		})
+	}
+
+	// the oldest is evicted
+	assert.Nil(t, cache.GetEntryCache("/zone/home/test/file0"))
+	assert.NotNil(t, cache.GetEntryCache("/zone/home/test/file1"))
+	assert.NotNil(t, cache.GetEntryCache("/zone/home/test/file2"))
+
+	// use file1, so file2 becomes the least recently used
+	assert.NotNil(t, cache.GetEntryCache("/zone/home/test/file1"))
+	cache.AddEntryCache(&fs.Entry{
+		ID:   303,
+		Type: fs.FileEntry,
+		Name: "file3",
+		Path: "/zone/home/test/file3",
+	})
+
+	assert.NotNil(t, cache.GetEntryCache("/zone/home/test/file1"))
+	assert.Nil(t, cache.GetEntryCache("/zone/home/test/file2"))
+
+	stats := cache.GetMetrics().GetCacheStats(fs.FileSystemCacheNameEntry)
+	assert.Equal(t, uint64(4), stats.Hit)
+	assert.Equal(t, uint64(2), stats.Miss)
+	assert.Equal(t, uint64(2), stats.Eviction)
+
+	// other caches are unlimited
+	for i := 0; i < 3; i++ {
+		cache.AddDirCache(fmt.Sprintf("/zone/home/test/dir%d", i), []string{})
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.NotNil(t, cache.GetDirCache(fmt.Sprintf("/zone/home/test/dir%d", i)))
+	}
+
+	assert.Equal(t, uint64(0), cache.GetMetrics().GetCacheStats(fs.FileSystemCacheNameDir).Eviction)
+}
+
+func testCacheMaxBytes(t *testing.T) {
+	cache := fs.NewFileSystemCache(time.Minute, time.Minute, nil, true)
+	cache.SetCacheLimit(fs.FileSystemCacheNameDir, fs.FileSystemCacheLimit{
+		MaxBytes: 4096,
+	})
+
+	largeDirEntries := []string{}
+	for i := 0; i < 100; i++ {
+		largeDirEntries = append(largeDirEntries, fmt.Sprintf("/zone/home/test/large/file%d", i))
+	}
+
+	cache.AddDirCache("/zone/home/test/small", []string{"/zone/home/test/small/file"})
+	cache.AddDirCache("/zone/home/test/large", largeDirEntries)
+
+	// the large dir exceeds the limit by itself
+	assert.Nil(t, cache.GetDirCache("/zone/home/test/small"))
+	assert.Nil(t, cache.GetDirCache("/zone/home/test/large"))
+
+	cache.AddDirCache("/zone/home/test/small", []string{"/zone/home/test/small/file"})
+	assert.NotNil(t, cache.GetDirCache("/zone/home/test/small"))
+}
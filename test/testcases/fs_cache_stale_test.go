@@ -0,0 +1,55 @@
+package testcases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFSCacheStale(t *testing.T) {
+	t.Run("test CacheStaleGracePeriod", testCacheStaleGracePeriod)
+}
+
+func testCacheStaleGracePeriod(t *testing.T) {
+	cache := fs.NewFileSystemCache(100*time.Millisecond, time.Minute, nil, true)
+	cache.SetStaleGracePeriod(500 * time.Millisecond)
+
+	entry := &fs.Entry{
+		ID:   400,
+		Type: fs.FileEntry,
+		Name: "file",
+		Path: "/zone/home/test/file",
+	}
+
+	cache.AddEntryCache(entry)
+	cache.AddDirCache("/zone/home/test", []string{entry.Path})
+
+	// not expired
+	assert.NotNil(t, cache.GetEntryCache(entry.Path))
+	assert.Nil(t, cache.GetStaleEntryCache(entry.Path))
+
+	time.Sleep(200 * time.Millisecond)
+
+	// expired, but within grace period
+	assert.Nil(t, cache.GetEntryCache(entry.Path))
+	assert.Nil(t, cache.GetDirCache("/zone/home/test"))
+	assert.NotNil(t, cache.GetStaleEntryCache(entry.Path))
+	assert.Equal(t, []string{entry.Path}, cache.GetStaleDirCache("/zone/home/test"))
+
+	// refreshed
+	cache.AddEntryCache(entry)
+	assert.NotNil(t, cache.GetEntryCache(entry.Path))
+
+	time.Sleep(700 * time.Millisecond)
+
+	// grace period is over
+	assert.Nil(t, cache.GetStaleEntryCache(entry.Path))
+	assert.Nil(t, cache.GetStaleDirCache("/zone/home/test"))
+
+	// invalidated caches are not served
+	cache.AddEntryCache(entry)
+	cache.RemoveEntryCache(entry.Path)
+	assert.Nil(t, cache.GetStaleEntryCache(entry.Path))
+}
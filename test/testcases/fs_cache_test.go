@@ -3,8 +3,11 @@ package testcases
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/connection"
+	irods_fs "github.com/cyverse/go-irodsclient/irods/fs"
 	"github.com/rs/xid"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +24,8 @@ func TestFSCache(t *testing.T) {
 
 	t.Run("test MakeDir", testMakeDir)
 	t.Run("test testMakeDirCacheEvent", testMakeDirCacheEvent)
+	t.Run("test RemoteChangeWatcher", testRemoteChangeWatcher)
+	t.Run("test StaleCacheRefresh", testStaleCacheRefresh)
 }
 
 func testMakeDir(t *testing.T) {
@@ -130,3 +135,148 @@ func testMakeDirCacheEvent(t *testing.T) {
 		eventPathsReceived = []string{}
 	}
 }
+
+func testRemoteChangeWatcher(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsCacheTestID)
+	watchdir := fmt.Sprintf("%s/watch_test_dir", homedir)
+
+	err = filesystem.MakeDir(watchdir, false)
+	failError(t, err)
+
+	// cache listing
+	_, err = filesystem.List(watchdir)
+	failError(t, err)
+
+	events := map[string]fs.FilesystemCacheEventType{}
+	filesystem.AddCacheEventHandler(func(path string, eventType fs.FilesystemCacheEventType) {
+		events[path] = eventType
+	})
+
+	watcher := fs.NewFileSystemWatcherWithDefault(filesystem)
+	defer watcher.Release()
+
+	err = watcher.WatchSince(watchdir, time.Now().Add(-time.Second))
+	failError(t, err)
+
+	assert.Equal(t, []string{watchdir}, watcher.GetWatchedPaths())
+
+	// changes made by another client
+	conn := connection.NewIRODSConnection(account, 300*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	failError(t, err)
+	defer conn.Disconnect()
+
+	newdir := fmt.Sprintf("%s/subdir", watchdir)
+	err = irods_fs.CreateCollection(conn, newdir, false)
+	failError(t, err)
+
+	newfile := fmt.Sprintf("%s/file.txt", watchdir)
+	handle, err := irods_fs.CreateDataObject(conn, newfile, "", "w", true)
+	failError(t, err)
+
+	err = irods_fs.WriteDataObject(conn, handle, []byte("remote change"))
+	failError(t, err)
+
+	err = irods_fs.CloseDataObject(conn, handle)
+	failError(t, err)
+
+	err = watcher.Poll()
+	failError(t, err)
+
+	assert.Equal(t, fs.FilesystemCacheDirCreateEvent, events[newdir])
+	assert.Equal(t, fs.FilesystemCacheFileCreateEvent, events[newfile])
+
+	entries, err := filesystem.List(watchdir)
+	failError(t, err)
+	assert.Equal(t, 2, len(entries))
+
+	// remove
+	events = map[string]fs.FilesystemCacheEventType{}
+
+	// iRODS keeps modification time in seconds
+	time.Sleep(time.Second)
+
+	err = irods_fs.DeleteDataObject(conn, newfile, true)
+	failError(t, err)
+
+	err = watcher.Poll()
+	failError(t, err)
+
+	assert.Equal(t, fs.FilesystemCacheFileRemoveEvent, events[newfile])
+	assert.False(t, filesystem.ExistsFile(newfile))
+
+	watcher.Unwatch(watchdir)
+	assert.Empty(t, watcher.GetWatchedPaths())
+
+	err = filesystem.RemoveDir(watchdir, true, true)
+	failError(t, err)
+}
+
+func testStaleCacheRefresh(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+	fsConfig.CacheTimeout = time.Second
+	fsConfig.CacheStaleGracePeriod = time.Minute
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsCacheTestID)
+	testdir := fmt.Sprintf("%s/stale_test_dir", homedir)
+
+	err = filesystem.MakeDir(testdir, false)
+	failError(t, err)
+
+	entries, err := filesystem.List(testdir)
+	failError(t, err)
+	assert.Equal(t, 0, len(entries))
+
+	// changes made by another client
+	conn := connection.NewIRODSConnection(account, 300*time.Second, "go-irodsclient-test")
+	err = conn.Connect()
+	failError(t, err)
+	defer conn.Disconnect()
+
+	newdir := fmt.Sprintf("%s/subdir", testdir)
+	err = irods_fs.CreateCollection(conn, newdir, false)
+	failError(t, err)
+
+	time.Sleep(1500 * time.Millisecond)
+
+	// stale listing is served while refreshing
+	entries, err = filesystem.List(testdir)
+	failError(t, err)
+	assert.Equal(t, 0, len(entries))
+
+	refreshed := false
+	for i := 0; i < 50; i++ {
+		entries, err = filesystem.List(testdir)
+		failError(t, err)
+
+		if len(entries) == 1 {
+			refreshed = true
+			break
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	assert.True(t, refreshed)
+
+	err = filesystem.RemoveDir(testdir, true, true)
+	failError(t, err)
+}
@@ -0,0 +1,174 @@
+package testcases
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataCache(t *testing.T) {
+	t.Run("test DataCacheBlocks", testDataCacheBlocks)
+	t.Run("test DataCacheEviction", testDataCacheEviction)
+	t.Run("test DataCacheValidate", testDataCacheValidate)
+	t.Run("test DataCacheValidateReplica", testDataCacheValidateReplica)
+}
+
+func testDataCacheBlocks(t *testing.T) {
+	rootPath := t.TempDir()
+
+	cache, err := fs.NewDataCache(rootPath, 1024, 1024*1024)
+	failError(t, err)
+
+	entry := &fs.Entry{
+		ID:         100,
+		Path:       "/zone/home/test/file",
+		ModifyTime: time.Now(),
+		CheckSum:   []byte{1, 2, 3},
+	}
+
+	cacheKey, err := cache.Validate(entry, nil)
+	failError(t, err)
+
+	assert.Nil(t, cache.GetBlock(cacheKey, 0))
+
+	block := bytes.Repeat([]byte{'a'}, 1024)
+	err = cache.PutBlock(cacheKey, 0, block)
+	failError(t, err)
+
+	assert.Equal(t, block, cache.GetBlock(cacheKey, 0))
+	assert.Equal(t, int64(1024), cache.GetSize())
+
+	// reload from disk
+	reloadedCache, err := fs.NewDataCache(rootPath, 1024, 1024*1024)
+	failError(t, err)
+
+	assert.Equal(t, int64(1024), reloadedCache.GetSize())
+	assert.Equal(t, block, reloadedCache.GetBlock(cacheKey, 0))
+
+	err = reloadedCache.Clear()
+	failError(t, err)
+	assert.Equal(t, int64(0), reloadedCache.GetSize())
+}
+
+func testDataCacheEviction(t *testing.T) {
+	cache, err := fs.NewDataCache(t.TempDir(), 1024, 3*1024)
+	failError(t, err)
+
+	entry := &fs.Entry{
+		ID:         101,
+		Path:       "/zone/home/test/file",
+		ModifyTime: time.Now(),
+	}
+
+	cacheKey, err := cache.Validate(entry, nil)
+	failError(t, err)
+
+	block := bytes.Repeat([]byte{'b'}, 1024)
+	for blockID := int64(0); blockID < 3; blockID++ {
+		err = cache.PutBlock(cacheKey, blockID, block)
+		failError(t, err)
+	}
+
+	// use block 0, so block 1 becomes the least recently used
+	assert.NotNil(t, cache.GetBlock(cacheKey, 0))
+
+	err = cache.PutBlock(cacheKey, 3, block)
+	failError(t, err)
+
+	assert.Equal(t, int64(3*1024), cache.GetSize())
+	assert.NotNil(t, cache.GetBlock(cacheKey, 0))
+	assert.Nil(t, cache.GetBlock(cacheKey, 1))
+	assert.NotNil(t, cache.GetBlock(cacheKey, 2))
+	assert.NotNil(t, cache.GetBlock(cacheKey, 3))
+}
+
+func testDataCacheValidate(t *testing.T) {
+	cache, err := fs.NewDataCache(t.TempDir(), 1024, 1024*1024)
+	failError(t, err)
+
+	entry := &fs.Entry{
+		ID:         102,
+		Path:       "/zone/home/test/file",
+		ModifyTime: time.Now(),
+	}
+
+	cacheKey, err := cache.Validate(entry, nil)
+	failError(t, err)
+
+	err = cache.PutBlock(cacheKey, 0, []byte("old content"))
+	failError(t, err)
+
+	// same version
+	sameCacheKey, err := cache.Validate(entry, nil)
+	failError(t, err)
+	assert.Equal(t, cacheKey, sameCacheKey)
+	assert.NotNil(t, cache.GetBlock(sameCacheKey, 0))
+
+	// modified
+	entry.ModifyTime = entry.ModifyTime.Add(time.Second)
+
+	newCacheKey, err := cache.Validate(entry, nil)
+	failError(t, err)
+	assert.NotEqual(t, cacheKey, newCacheKey)
+	assert.Nil(t, cache.GetBlock(newCacheKey, 0))
+	assert.Nil(t, cache.GetBlock(cacheKey, 0))
+	assert.Equal(t, int64(0), cache.GetSize())
+}
+
+func testDataCacheValidateReplica(t *testing.T) {
+	cache, err := fs.NewDataCache(t.TempDir(), 1024, 1024*1024)
+	failError(t, err)
+
+	// no checksum, modification time has second precision
+	entry := &fs.Entry{
+		ID:         103,
+		Path:       "/zone/home/test/file",
+		Size:       100,
+		ModifyTime: time.Unix(1700000000, 0),
+	}
+
+	replica0 := &types.IRODSFileDescriptorInfo{
+		ReplicaNumber:     0,
+		ResourceHierarchy: "demoResc",
+		DataSize:          100,
+	}
+
+	cacheKey, err := cache.Validate(entry, replica0)
+	failError(t, err)
+
+	err = cache.PutBlock(cacheKey, 0, []byte("replica 0"))
+	failError(t, err)
+
+	// rewritten in the same second with a different size
+	entry.Size = 200
+	replica0.DataSize = 200
+
+	newCacheKey, err := cache.Validate(entry, replica0)
+	failError(t, err)
+	assert.NotEqual(t, cacheKey, newCacheKey)
+	assert.Nil(t, cache.GetBlock(newCacheKey, 0))
+
+	err = cache.PutBlock(newCacheKey, 0, []byte("replica 0 rewritten"))
+	failError(t, err)
+
+	// another replica does not share or remove blocks
+	replica1 := &types.IRODSFileDescriptorInfo{
+		ReplicaNumber:     1,
+		ResourceHierarchy: "otherResc",
+		DataSize:          200,
+	}
+
+	replicaCacheKey, err := cache.Validate(entry, replica1)
+	failError(t, err)
+	assert.NotEqual(t, newCacheKey, replicaCacheKey)
+	assert.Nil(t, cache.GetBlock(replicaCacheKey, 0))
+
+	sameCacheKey, err := cache.Validate(entry, replica0)
+	failError(t, err)
+	assert.Equal(t, newCacheKey, sameCacheKey)
+	assert.Equal(t, []byte("replica 0 rewritten"), cache.GetBlock(sameCacheKey, 0))
+}
@@ -3,8 +3,12 @@ package testcases
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	io_fs "io/fs"
+	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -34,6 +38,15 @@ func TestFS(t *testing.T) {
 	t.Run("test WriteRename", testWriteRename)
 	t.Run("test WriteRenameDir", testWriteRenameDir)
 	t.Run("test RemoveClose", testRemoveClose)
+	t.Run("test IOFS", testIOFS)
+	t.Run("test Walk", testWalk)
+	t.Run("test WalkLookahead", testWalkLookahead)
+	t.Run("test UploadFileAtomic", testUploadFileAtomic)
+	t.Run("test ReadAhead", testReadAhead)
+	t.Run("test WriteBuffer", testWriteBuffer)
+	t.Run("test ParallelReadAt", testParallelReadAt)
+	t.Run("test SharedReplicaWrite", testSharedReplicaWrite)
+	t.Run("test FileHandleStat", testFileHandleStat)
 }
 
 func testPrepareSamplesForFS(t *testing.T) {
@@ -464,3 +477,611 @@ func testRemoveClose(t *testing.T) {
 	wg.Wait()
 	assert.False(t, filesystem.Exists(newDataObjectPath))
 }
+
+func testIOFS(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+	iofs := filesystem.GetIOFS(homedir)
+
+	// walk
+	walkedPaths := []string{}
+	err = io_fs.WalkDir(iofs, ".", func(p string, d io_fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p != "." {
+			walkedPaths = append(walkedPaths, homedir+"/"+p)
+		}
+		return nil
+	})
+	failError(t, err)
+
+	for _, testFilePath := range GetTestFiles() {
+		assert.Contains(t, walkedPaths, testFilePath)
+	}
+
+	for _, testDirPath := range GetTestDirs() {
+		assert.Contains(t, walkedPaths, testDirPath)
+	}
+
+	// read
+	testFilePath := GetTestFiles()[1]
+	testFileName := testFilePath[len(homedir)+1:]
+
+	stat, err := io_fs.Stat(iofs, testFileName)
+	failError(t, err)
+	assert.False(t, stat.IsDir())
+
+	data, err := io_fs.ReadFile(iofs, testFileName)
+	failError(t, err)
+	assert.Equal(t, stat.Size(), int64(len(data)))
+
+	f, err := iofs.Open(testFileName)
+	failError(t, err)
+
+	if readerAt, ok := f.(io.ReaderAt); ok {
+		buffer := make([]byte, 10)
+		readLen, err := readerAt.ReadAt(buffer, 5)
+		failError(t, err)
+		assert.Equal(t, data[5:5+readLen], buffer[:readLen])
+	} else {
+		assert.Fail(t, "opened file does not implement io.ReaderAt")
+	}
+
+	err = f.Close()
+	failError(t, err)
+
+	// errors
+	_, err = io_fs.Stat(iofs, "no_such_file_"+xid.New().String())
+	assert.True(t, errors.Is(err, io_fs.ErrNotExist))
+
+	_, err = iofs.Open("../escape")
+	assert.True(t, errors.Is(err, io_fs.ErrInvalid))
+}
+
+func testWalk(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	for _, flatQuery := range []bool{false, true} {
+		walkOptions := fs.NewWalkOptionsWithDefault()
+		walkOptions.FlatQuery = flatQuery
+		walkOptions.Sorted = true
+
+		walkedPaths := []string{}
+		err = filesystem.Walk(homedir, func(p string, entry *fs.Entry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			walkedPaths = append(walkedPaths, p)
+			return nil
+		}, walkOptions)
+		failError(t, err)
+
+		assert.Equal(t, homedir, walkedPaths[0])
+
+		for _, testFilePath := range GetTestFiles() {
+			assert.Contains(t, walkedPaths, testFilePath)
+		}
+
+		for _, testDirPath := range GetTestDirs() {
+			assert.Contains(t, walkedPaths, testDirPath)
+		}
+
+		// skip dirs
+		skippedPaths := []string{}
+		err = filesystem.Walk(homedir, func(p string, entry *fs.Entry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			skippedPaths = append(skippedPaths, p)
+			if entry.IsDir() && p != homedir {
+				return fs.SkipDir
+			}
+			return nil
+		}, walkOptions)
+		failError(t, err)
+
+		for _, testDirPath := range GetTestDirs() {
+			assert.Contains(t, skippedPaths, testDirPath)
+			for _, skippedPath := range skippedPaths {
+				assert.False(t, strings.HasPrefix(skippedPath, testDirPath+"/"))
+			}
+		}
+	}
+}
+
+func testWalkLookahead(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+	newdir := fmt.Sprintf("%s/testdir_%s", homedir, xid.New().String())
+
+	subDirNum := 12
+	for i := 0; i < subDirNum; i++ {
+		err = filesystem.MakeDir(fmt.Sprintf("%s/subdir_%02d", newdir, i), true)
+		failError(t, err)
+	}
+
+	defer filesystem.RemoveDir(newdir, true, true)
+
+	// walk with a new filesystem, so collections are not listed from cache
+	walkFilesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer walkFilesystem.Release()
+
+	walkOptions := fs.NewWalkOptionsWithDefault()
+	walkOptions.WorkerNum = 2
+	walkOptions.Sorted = true
+
+	// each listing of a collection lists sub collections and data objects
+	listsPerCollection := uint64(2)
+
+	var listsBefore uint64
+	walkedDirs := 0
+	err = walkFilesystem.Walk(newdir, func(p string, entry *fs.Entry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p == newdir {
+			listsBefore = walkFilesystem.GetMetrics().GetCounterForList()
+			return nil
+		}
+
+		if walkedDirs == 0 {
+			// give workers time to list collections submitted
+			time.Sleep(1 * time.Second)
+
+			// the root and the next sibling collections within the lookahead are listed
+			lists := walkFilesystem.GetMetrics().GetCounterForList() - listsBefore
+			assert.LessOrEqual(t, lists, listsPerCollection*uint64(1+walkOptions.WorkerNum))
+		}
+
+		walkedDirs++
+		return nil
+	}, walkOptions)
+	failError(t, err)
+
+	assert.Equal(t, subDirNum, walkedDirs)
+}
+
+func testUploadFileAtomic(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	filename := "test_atomic_upload.bin"
+	fileSize := 10 * 1024 * 1024 // 10MB
+
+	localPath, err := createLocalTestFile(filename, int64(fileSize))
+	failError(t, err)
+	defer os.Remove(localPath)
+
+	irodsPath := homedir + "/" + filename
+
+	err = filesystem.UploadFileAtomic(localPath, irodsPath, "", 1, false, false, true, nil)
+	failError(t, err)
+
+	entry, err := filesystem.Stat(irodsPath)
+	failError(t, err)
+	assert.Equal(t, int64(fileSize), entry.Size)
+
+	// fail without overwrite
+	err = filesystem.UploadFileAtomic(localPath, irodsPath, "", 1, false, false, true, nil)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrExist))
+
+	// overwrite in parallel
+	err = filesystem.UploadFileAtomic(localPath, irodsPath, "", 4, false, true, true, nil)
+	failError(t, err)
+
+	// no temporary data objects left
+	entries, err := filesystem.List(homedir)
+	failError(t, err)
+
+	for _, entry := range entries {
+		assert.False(t, strings.HasPrefix(entry.Name, "."+filename))
+	}
+
+	err = filesystem.RemoveFile(irodsPath, true)
+	failError(t, err)
+}
+
+func testReadAhead(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+	fsConfig.ReadAheadBlockSize = 64 * 1024
+	fsConfig.ReadAheadWindow = 4
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	filename := "test_read_ahead.bin"
+	fileSize := 1024*1024 + 123
+
+	localPath, err := createLocalTestFile(filename, int64(fileSize))
+	failError(t, err)
+	defer os.Remove(localPath)
+
+	localData, err := os.ReadFile(localPath)
+	failError(t, err)
+
+	irodsPath := homedir + "/" + filename
+
+	err = filesystem.UploadFile(localPath, irodsPath, "", false, nil)
+	failError(t, err)
+
+	handle, err := filesystem.OpenFile(irodsPath, "", "r")
+	failError(t, err)
+
+	// sequential reads
+	readData := []byte{}
+	buffer := make([]byte, 4096)
+	for {
+		readLen, err := handle.Read(buffer)
+		readData = append(readData, buffer[:readLen]...)
+		if err == io.EOF {
+			break
+		}
+		failError(t, err)
+	}
+
+	assert.Equal(t, localData, readData)
+
+	// random reads
+	offsets := []int64{100, 300000, 4096, int64(fileSize) - 10}
+	for _, offset := range offsets {
+		readLen, err := handle.ReadAt(buffer[:10], offset)
+		if err != nil && err != io.EOF {
+			failError(t, err)
+		}
+		assert.Equal(t, localData[offset:offset+int64(readLen)], buffer[:readLen])
+	}
+
+	// requests on the handle while blocks are prefetched
+	_, err = handle.Seek(0, io.SeekStart)
+	failError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = io.ReadFull(handle, buffer)
+		failError(t, err)
+	}
+
+	_, err = handle.Stat()
+	failError(t, err)
+
+	_, err = io.ReadFull(handle, buffer)
+	failError(t, err)
+	assert.Equal(t, localData[3*len(buffer):4*len(buffer)], buffer)
+
+	newOffset, err := handle.Seek(100, io.SeekCurrent)
+	failError(t, err)
+	assert.Equal(t, int64(4*len(buffer)+100), newOffset)
+
+	_, err = io.ReadFull(handle, buffer)
+	failError(t, err)
+	assert.Equal(t, localData[newOffset:newOffset+int64(len(buffer))], buffer)
+
+	err = handle.Close()
+	failError(t, err)
+
+	err = filesystem.RemoveFile(irodsPath, true)
+	failError(t, err)
+}
+
+func testWriteBuffer(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+	fsConfig.WriteBufferSize = 64 * 1024
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	newDataObjectFilename := "testobj_" + xid.New().String()
+	newDataObjectPath := homedir + "/" + newDataObjectFilename
+
+	handle, err := filesystem.CreateFile(newDataObjectPath, "", "w")
+	failError(t, err)
+
+	// many small writes
+	expected := []byte{}
+	for i := 0; i < 10000; i++ {
+		line := []byte(fmt.Sprintf("%d,line,%d\n", i, i*i))
+		expected = append(expected, line...)
+
+		_, err = handle.Write(line)
+		failError(t, err)
+	}
+
+	// overwrite the head
+	_, err = handle.WriteAt([]byte("HEAD"), 0)
+	failError(t, err)
+	copy(expected, []byte("HEAD"))
+
+	// lock while a flush may be in flight, then write under the lock
+	err = handle.LockDataObject(true)
+	failError(t, err)
+
+	tail := make([]byte, 200*1024)
+	for i := range tail {
+		tail[i] = byte(i % 251)
+	}
+	tailOffset := int64(len(expected))
+	expected = append(expected, tail...)
+
+	_, err = handle.WriteAt(tail, tailOffset)
+	failError(t, err)
+
+	err = handle.UnlockDataObject()
+	failError(t, err)
+
+	err = handle.Flush()
+	failError(t, err)
+
+	err = handle.Close()
+	failError(t, err)
+
+	entry, err := filesystem.Stat(newDataObjectPath)
+	failError(t, err)
+	assert.Equal(t, int64(len(expected)), entry.Size)
+
+	// read
+	readHandle, err := filesystem.OpenFile(newDataObjectPath, "", "r")
+	failError(t, err)
+
+	readData := make([]byte, len(expected))
+	readLen, err := io.ReadFull(readHandle, readData)
+	failError(t, err)
+
+	err = readHandle.Close()
+	failError(t, err)
+
+	assert.Equal(t, expected, readData[:readLen])
+
+	err = filesystem.RemoveFile(newDataObjectPath, true)
+	failError(t, err)
+}
+
+func testParallelReadAt(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	filename := "test_parallel_read_at.bin"
+	fileSize := 4 * 1024 * 1024
+
+	localPath, err := createLocalTestFile(filename, int64(fileSize))
+	failError(t, err)
+	defer os.Remove(localPath)
+
+	localData, err := os.ReadFile(localPath)
+	failError(t, err)
+
+	irodsPath := homedir + "/" + filename
+
+	err = filesystem.UploadFile(localPath, irodsPath, "", false, nil)
+	failError(t, err)
+
+	handle, err := filesystem.OpenFileForParallelRead(irodsPath, "", 4)
+	failError(t, err)
+
+	var readerAt io.ReaderAt = handle
+
+	chunkSize := 256 * 1024
+	wg := sync.WaitGroup{}
+	for offset := 0; offset < fileSize; offset += chunkSize {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+
+			buffer := make([]byte, chunkSize)
+			readLen, err := readerAt.ReadAt(buffer, int64(offset))
+			assert.NoError(t, err)
+			assert.Equal(t, chunkSize, readLen)
+			assert.Equal(t, localData[offset:offset+chunkSize], buffer[:readLen])
+		}(offset)
+	}
+	wg.Wait()
+
+	// read beyond EOF
+	buffer := make([]byte, 100)
+	readLen, err := readerAt.ReadAt(buffer, int64(fileSize-10))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 10, readLen)
+
+	err = handle.Close()
+	failError(t, err)
+
+	err = filesystem.RemoveFile(irodsPath, true)
+	failError(t, err)
+}
+
+func testSharedReplicaWrite(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	newDataObjectFilename := "testobj_" + xid.New().String()
+	newDataObjectPath := homedir + "/" + newDataObjectFilename
+
+	writerNum := 4
+	chunkSize := 1024 * 1024
+	dataSize := writerNum * chunkSize
+
+	expected := make([]byte, dataSize)
+	for i := range expected {
+		expected[i] = byte(i % 251)
+	}
+
+	primaryHandle, err := filesystem.CreateFileForSharedWrite(newDataObjectPath, "", writerNum, int64(dataSize))
+	failError(t, err)
+
+	info, err := primaryHandle.GetReplicaAccessInfo()
+	failError(t, err)
+	assert.NotEmpty(t, info.ReplicaToken)
+	assert.NotEmpty(t, info.ResourceHierarchy)
+
+	// the primary handle writes the first chunk
+	_, err = primaryHandle.WriteAt(expected[:chunkSize], 0)
+	failError(t, err)
+
+	// writers are closed after the primary handle
+	primaryClosed := make(chan bool)
+
+	wg := sync.WaitGroup{}
+	for i := 1; i < writerNum; i++ {
+		writer, err := primaryHandle.OpenSharedWriter()
+		failError(t, err)
+
+		wg.Add(1)
+		go func(writer *fs.FileHandle, offset int) {
+			defer wg.Done()
+
+			_, err := writer.WriteAt(expected[offset:offset+chunkSize], int64(offset))
+			assert.NoError(t, err)
+
+			<-primaryClosed
+
+			err = writer.Close()
+			assert.NoError(t, err)
+		}(writer, i*chunkSize)
+	}
+
+	// the replica is finalized after all writers are closed
+	err = primaryHandle.Close()
+	failError(t, err)
+
+	// the primary handle can't be used or closed again while writers are open
+	_, err = primaryHandle.WriteAt(expected[:chunkSize], 0)
+	assert.Error(t, err)
+
+	err = primaryHandle.Close()
+	assert.Error(t, err)
+
+	close(primaryClosed)
+	wg.Wait()
+
+	entry, err := filesystem.StatFile(newDataObjectPath)
+	failError(t, err)
+	assert.Equal(t, int64(dataSize), entry.Size)
+
+	readHandle, err := filesystem.OpenFile(newDataObjectPath, "", "r")
+	failError(t, err)
+
+	readData := make([]byte, dataSize)
+	_, err = io.ReadFull(readHandle, readData)
+	failError(t, err)
+
+	err = readHandle.Close()
+	failError(t, err)
+
+	assert.Equal(t, expected, readData)
+
+	err = filesystem.RemoveFile(newDataObjectPath, true)
+	failError(t, err)
+}
+
+func testFileHandleStat(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	fsConfig := fs.NewFileSystemConfigWithDefault("go-irodsclient-test")
+
+	filesystem, err := fs.NewFileSystem(account, fsConfig)
+	failError(t, err)
+	defer filesystem.Release()
+
+	homedir := getHomeDir(fsTestID)
+
+	newDataObjectFilename := "testobj_" + xid.New().String()
+	newDataObjectPath := homedir + "/" + newDataObjectFilename
+
+	text := "HELLO WORLD"
+
+	handle, err := filesystem.CreateFile(newDataObjectPath, "", "w")
+	failError(t, err)
+
+	_, err = handle.Write([]byte(text))
+	failError(t, err)
+
+	info, err := handle.Stat()
+	failError(t, err)
+
+	assert.Equal(t, newDataObjectPath, info.Path)
+	assert.NotEmpty(t, info.ResourceHierarchy)
+	assert.NotEmpty(t, info.PhysicalPath)
+	assert.Equal(t, int64(len(text)), info.BytesWritten)
+
+	err = handle.Close()
+	failError(t, err)
+
+	err = filesystem.RemoveFile(newDataObjectPath, true)
+	failError(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/cyverse/go-irodsclient/icommands"
+	"github.com/cyverse/go-irodsclient/irods/types"
 	"github.com/cyverse/go-irodsclient/test/server"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,6 +15,7 @@ func TestIRODSEnvironment(t *testing.T) {
 	t.Run("test SaveAndLoadEnv", testSaveAndLoadEnv)
 	t.Run("test SaveAndLoadEnvSession", testSaveAndLoadEnvSession)
 	t.Run("test ConfiguredAuthFilePath", testConfiguredAuthFilePath)
+	t.Run("test SavePAMToken", testSavePAMToken)
 }
 
 func testSaveAndLoadEnv(t *testing.T) {
@@ -127,3 +129,37 @@ func testSaveAndLoadEnvSession(t *testing.T) {
 	err = os.RemoveAll("~/.irods2")
 	failError(t, err)
 }
+
+func testSavePAMToken(t *testing.T) {
+	account, err := types.CreateIRODSAccount("localhost", 1247, "test", "tempZone", types.AuthSchemePAM, "test_password", "")
+	failError(t, err)
+
+	envMgr, err := icommands.CreateIcommandsEnvironmentManagerFromIRODSAccount(account)
+	failError(t, err)
+
+	dir := t.TempDir()
+
+	envFilePath := path.Join(dir, "irods_environment.json")
+	err = envMgr.SetEnvironmentFilePath(envFilePath)
+	failError(t, err)
+
+	envMgr.Environment.AuthenticationFile = path.Join(dir, "auth", "irodsA")
+	err = envMgr.Environment.ToFile(envFilePath)
+	failError(t, err)
+
+	err = envMgr.SavePAMToken("refreshed_pam_token")
+	failError(t, err)
+	assert.Equal(t, "refreshed_pam_token", envMgr.PamToken)
+
+	envMgr2, err := icommands.CreateIcommandsEnvironmentManager()
+	failError(t, err)
+
+	err = envMgr2.SetEnvironmentFilePath(envFilePath)
+	failError(t, err)
+
+	err = envMgr2.Load(os.Getppid())
+	failError(t, err)
+
+	assert.Equal(t, "refreshed_pam_token", envMgr2.PamToken)
+	assert.Empty(t, envMgr2.Password)
+}
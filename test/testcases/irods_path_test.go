@@ -0,0 +1,30 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIRODSPath(t *testing.T) {
+	t.Run("test IsIRODSSubPath", testIsIRODSSubPath)
+	t.Run("test MakeIRODSLikePattern", testMakeIRODSLikePattern)
+}
+
+func testIsIRODSSubPath(t *testing.T) {
+	assert.True(t, util.IsIRODSSubPath("/zone/home", "/zone/home/user"))
+	assert.True(t, util.IsIRODSSubPath("/zone/home/", "/zone/home/user/dir"))
+	assert.False(t, util.IsIRODSSubPath("/zone/home", "/zone/home"))
+	assert.False(t, util.IsIRODSSubPath("/zone/home", "/zone/home2/user"))
+	assert.False(t, util.IsIRODSSubPath("/zone/home", "/zone"))
+}
+
+func testMakeIRODSLikePattern(t *testing.T) {
+	assert.Equal(t, `/zone/home/my\_dir/%`, util.MakeIRODSSubPathLikePattern("/zone/home/my_dir/"))
+	assert.Equal(t, `/zone/home/100\%/%`, util.MakeIRODSSubPathLikePattern("/zone/home/100%"))
+	assert.Equal(t, `/zone/home/a\\b/%`, util.MakeIRODSSubPathLikePattern(`/zone/home/a\b`))
+
+	// single quotes are matched with a wildcard
+	assert.Equal(t, `/zone/home/user_s/%`, util.MakeIRODSSubPathLikePattern("/zone/home/user's"))
+}
@@ -0,0 +1,167 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/cyverse/go-irodsclient/irods/session"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePAMServer issues pam tokens for login with password, and accepts only the latest token
+type fakePAMServer struct {
+	password   string
+	validToken string
+	logins     int
+	// connectBarrier holds connections using expired tokens until all of them try, if set
+	connectBarrier *sync.WaitGroup
+	mutex          sync.Mutex
+}
+
+// fakePAMConnection is a connection created with the account shared with PAMTokenRefresher
+type fakePAMConnection struct {
+	account *types.IRODSAccount
+	server  *fakePAMServer
+}
+
+func (conn *fakePAMConnection) Connect() error {
+	if len(conn.account.PamToken) == 0 {
+		conn.server.mutex.Lock()
+		defer conn.server.mutex.Unlock()
+
+		if conn.account.Password != conn.server.password {
+			return types.NewAuthError(conn.account)
+		}
+
+		conn.server.logins++
+		conn.server.validToken = fmt.Sprintf("token_%d", conn.server.logins)
+		conn.account.PamToken = conn.server.validToken
+		return nil
+	}
+
+	pamToken := conn.account.PamToken
+
+	conn.server.mutex.Lock()
+	validToken := conn.server.validToken
+	barrier := conn.server.connectBarrier
+	conn.server.mutex.Unlock()
+
+	if pamToken != validToken {
+		if barrier != nil {
+			barrier.Done()
+			barrier.Wait()
+		}
+		return types.NewAuthError(conn.account)
+	}
+	return nil
+}
+
+func TestPAMTokenRefresher(t *testing.T) {
+	t.Run("test RefreshExpiredToken", testRefreshExpiredPAMToken)
+	t.Run("test RefreshConcurrently", testRefreshPAMTokenConcurrently)
+	t.Run("test RefreshFailure", testRefreshPAMTokenFailure)
+}
+
+// newFakePAMAccount creates an account having an expired pam token
+func newFakePAMAccount(t *testing.T) *types.IRODSAccount {
+	account, err := types.CreateIRODSAccount("irods.example.com", 1247, "test", "tempZone", types.AuthSchemePAM, "test_password", "")
+	failError(t, err)
+
+	account.PamToken = "expired_token"
+	return account
+}
+
+func testRefreshExpiredPAMToken(t *testing.T) {
+	account := newFakePAMAccount(t)
+	server := &fakePAMServer{
+		password:   "test_password",
+		validToken: "",
+	}
+
+	handled := []string{}
+	refresher := session.NewPAMTokenRefresher(account, func(pamToken string) {
+		handled = append(handled, pamToken)
+	})
+
+	err := refresher.Connect(&fakePAMConnection{account: account, server: server})
+	failError(t, err)
+
+	assert.Equal(t, 1, server.logins)
+	assert.Equal(t, "token_1", account.PamToken)
+	assert.Equal(t, []string{"token_1"}, handled)
+
+	// the new token is reused
+	err = refresher.Connect(&fakePAMConnection{account: account, server: server})
+	failError(t, err)
+
+	assert.Equal(t, 1, server.logins)
+	assert.Equal(t, []string{"token_1"}, handled)
+}
+
+func testRefreshPAMTokenConcurrently(t *testing.T) {
+	connections := 8
+
+	account := newFakePAMAccount(t)
+	server := &fakePAMServer{
+		password:       "test_password",
+		validToken:     "",
+		connectBarrier: &sync.WaitGroup{},
+	}
+
+	// all connections fail with the expired token before any of them refreshes
+	server.connectBarrier.Add(connections)
+
+	handlerMutex := sync.Mutex{}
+	handled := []string{}
+	refresher := session.NewPAMTokenRefresher(account, func(pamToken string) {
+		handlerMutex.Lock()
+		defer handlerMutex.Unlock()
+
+		handled = append(handled, pamToken)
+	})
+
+	errs := make([]error, connections)
+	waitGroup := sync.WaitGroup{}
+	for i := 0; i < connections; i++ {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+
+			errs[i] = refresher.Connect(&fakePAMConnection{account: account, server: server})
+		}(i)
+	}
+	waitGroup.Wait()
+
+	for _, err := range errs {
+		failError(t, err)
+	}
+
+	// login with password is done once, others use the refreshed token
+	assert.Equal(t, 1, server.logins)
+	assert.Equal(t, "token_1", account.PamToken)
+	assert.Equal(t, []string{"token_1"}, handled)
+}
+
+func testRefreshPAMTokenFailure(t *testing.T) {
+	account := newFakePAMAccount(t)
+	server := &fakePAMServer{
+		password:   "changed_password",
+		validToken: "",
+	}
+
+	handled := []string{}
+	refresher := session.NewPAMTokenRefresher(account, func(pamToken string) {
+		handled = append(handled, pamToken)
+	})
+
+	err := refresher.Connect(&fakePAMConnection{account: account, server: server})
+	assert.Error(t, err)
+	assert.True(t, types.IsAuthError(err))
+
+	// the old token is kept for next trials
+	assert.Equal(t, "expired_token", account.PamToken)
+	assert.Equal(t, 0, server.logins)
+	assert.Empty(t, handled)
+}
@@ -1,7 +1,10 @@
 package testcases
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/cyverse/go-irodsclient/irods/connection"
 	"github.com/cyverse/go-irodsclient/irods/fs"
@@ -24,6 +27,11 @@ func TestSession(t *testing.T) {
 	t.Run("test Session", testSession)
 	t.Run("test many Connections", testManyConnections)
 	t.Run("test Connection Metrics", testConnectionMetrics)
+	t.Run("test Retry", testRetry)
+	t.Run("test ConnectionWaitQueue", testConnectionWaitQueue)
+	t.Run("test ConnectionHealthCheck", testConnectionHealthCheck)
+	t.Run("test SwitchUser", testSwitchUser)
+	t.Run("test EndpointFailover", testEndpointFailover)
 }
 
 func testSession(t *testing.T) {
@@ -136,3 +144,309 @@ func testConnectionMetrics(t *testing.T) {
 	assert.Equal(t, uint64(sessionConfig.ConnectionMaxIdle), metrics.GetConnectionsOpened())
 	assert.Equal(t, uint64(0), metrics.GetConnectionsOccupied())
 }
+
+func testRetry(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+	account.CSNegotiationPolicy = types.CSNegotiationDontCare
+
+	sessionConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	homedir := getHomeDir(fsSessionTestID)
+
+	// break the connection at the first attempt
+	attempts := 0
+	err = sess.RunWithRetry(func(conn *connection.IRODSConnection) error {
+		attempts++
+		if attempts == 1 {
+			conn.Disconnect()
+		}
+
+		_, statErr := fs.GetCollection(conn, homedir)
+		return statErr
+	})
+	failError(t, err)
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, uint64(1), sess.GetMetrics().GetCounterForRetries())
+
+	// mutating operations are not retried by default
+	attempts = 0
+	err = sess.RunMutatingWithRetry(func(conn *connection.IRODSConnection) error {
+		attempts++
+		conn.Disconnect()
+
+		_, statErr := fs.GetCollection(conn, homedir)
+		return statErr
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func testConnectionWaitQueue(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+	account.CSNegotiationPolicy = types.CSNegotiationDontCare
+
+	sessionConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessionConfig.ConnectionMax = session.IRODSSessionConnectionMaxMin
+	sessionConfig.ConnectionReservedHighPriority = 1
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	connections := []*connection.IRODSConnection{}
+
+	// low priority can't occupy the reserved connection
+	for i := 0; i < sessionConfig.ConnectionMax-1; i++ {
+		conn, err := sess.AcquireConnectionWithContext(context.Background(), session.ConnectionPriorityLow)
+		failError(t, err)
+
+		connections = append(connections, conn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err = sess.AcquireConnectionWithContext(ctx, session.ConnectionPriorityLow)
+	assert.Error(t, err)
+	assert.True(t, types.IsConnectionPoolFullError(err))
+
+	// high priority can
+	conn, err := sess.AcquireConnectionWithContext(context.Background(), session.ConnectionPriorityHigh)
+	failError(t, err)
+
+	connections = append(connections, conn)
+
+	// wait until a connection is returned
+	acquiredCh := make(chan *connection.IRODSConnection)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		waitedConn, waitErr := sess.AcquireConnectionWithContext(ctx, session.ConnectionPriorityHigh)
+		if waitErr != nil {
+			acquiredCh <- nil
+			return
+		}
+		acquiredCh <- waitedConn
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+
+	err = sess.ReturnConnection(connections[0])
+	failError(t, err)
+	connections = connections[1:]
+
+	waitedConn := <-acquiredCh
+	assert.NotNil(t, waitedConn)
+	assert.Equal(t, sessionConfig.ConnectionMax, sess.ConnectionTotal())
+	assert.Equal(t, uint64(2), sess.GetMetrics().GetCounterForConnectionPoolWaits())
+
+	if waitedConn != nil {
+		connections = append(connections, waitedConn)
+	}
+
+	for _, conn := range connections {
+		err = sess.ReturnConnection(conn)
+		failError(t, err)
+	}
+}
+
+// breakConnection replaces the socket of the connection with a closed one, so the connection looks alive but is dead
+func breakConnection(conn *connection.IRODSConnection) {
+	client, server := net.Pipe()
+	server.Close()
+
+	conn.Disconnect()
+	conn.RawBind(client)
+}
+
+func testConnectionHealthCheck(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+	account.CSNegotiationPolicy = types.CSNegotiationDontCare
+
+	// validate on borrow
+	sessionConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessionConfig.ConnectionValidateOnBorrow = true
+	sessionConfig.TCPKeepAlive = 30 * time.Second
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+
+	conn.Lock()
+	err = conn.Ping()
+	conn.Unlock()
+	failError(t, err)
+
+	breakConnection(conn)
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+
+	newConn, err := sess.AcquireConnection()
+	failError(t, err)
+	assert.NotEqual(t, conn, newConn)
+	assert.Equal(t, uint64(1), sess.GetMetrics().GetCounterForConnectionPoolEvictions())
+
+	newConn.Lock()
+	err = newConn.Ping()
+	newConn.Unlock()
+	failError(t, err)
+
+	err = sess.ReturnConnection(newConn)
+	failError(t, err)
+
+	// background health check
+	sessionConfig2 := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+	sessionConfig2.ConnectionHealthCheckInterval = 100 * time.Millisecond
+
+	sess2, err := session.NewIRODSSession(account, sessionConfig2)
+	failError(t, err)
+	defer sess2.Release()
+
+	conn2, err := sess2.AcquireConnection()
+	failError(t, err)
+
+	breakConnection(conn2)
+
+	err = sess2.ReturnConnection(conn2)
+	failError(t, err)
+
+	time.Sleep(1 * time.Second)
+
+	assert.Equal(t, uint64(1), sess2.GetMetrics().GetCounterForConnectionPoolEvictions())
+
+	// the dead connection is replaced
+	assert.Equal(t, 1, sess2.ConnectionTotal())
+	assert.False(t, conn2.IsConnected())
+}
+
+func testSwitchUser(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+	account.CSNegotiationPolicy = types.CSNegotiationDontCare
+
+	sessionConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	conn, err := sess.AcquireConnection()
+	failError(t, err)
+
+	assert.Equal(t, account.ClientUser, conn.GetClientUser())
+	assert.Equal(t, account.ClientZone, conn.GetClientZone())
+
+	testUsername := "test_switch_user"
+
+	err = fs.CreateUser(conn, testUsername, account.ClientZone, "rodsuser")
+	failError(t, err)
+
+	defer func() {
+		adminConn, err := sess.AcquireConnection()
+		failError(t, err)
+
+		err = fs.RemoveUser(adminConn, testUsername, account.ClientZone)
+		failError(t, err)
+
+		err = sess.ReturnConnection(adminConn)
+		failError(t, err)
+	}()
+
+	supportSwitchUser := conn.SupportSwitchUser()
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+
+	if !supportSwitchUser {
+		_, err = sess.AcquireConnectionAs(testUsername, account.ClientZone)
+		assert.Error(t, err)
+		return
+	}
+
+	// switch to the test user
+	userConn, err := sess.AcquireConnectionAs(testUsername, account.ClientZone)
+	failError(t, err)
+
+	assert.Equal(t, testUsername, userConn.GetClientUser())
+	assert.Equal(t, account.ClientZone, userConn.GetClientZone())
+
+	err = sess.ReturnConnection(userConn)
+	failError(t, err)
+
+	// switch back on checkout
+	conn, err = sess.AcquireConnection()
+	failError(t, err)
+
+	assert.Equal(t, account.ClientUser, conn.GetClientUser())
+
+	collection, err := fs.GetCollection(conn, getHomeDir(fsSessionTestID))
+	failError(t, err)
+	assert.Equal(t, getHomeDir(fsSessionTestID), collection.Path)
+
+	err = sess.ReturnConnection(conn)
+	failError(t, err)
+}
+
+func testEndpointFailover(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+	account.CSNegotiationPolicy = types.CSNegotiationDontCare
+
+	// the first endpoint is dead
+	serverEndpoint := types.IRODSEndpoint{
+		Host: account.Host,
+		Port: account.Port,
+	}
+
+	account.Host = "127.0.0.1"
+	account.Port = getClosedPort(t)
+	account.AdditionalEndpoints = []types.IRODSEndpoint{serverEndpoint}
+
+	sessionConfig := session.NewIRODSSessionConfigWithDefault("go-irodsclient-test")
+
+	sess, err := session.NewIRODSSession(account, sessionConfig)
+	failError(t, err)
+	defer sess.Release()
+
+	connections := []*connection.IRODSConnection{}
+	for i := 0; i < 3; i++ {
+		conn, err := sess.AcquireConnection()
+		failError(t, err)
+
+		assert.Equal(t, serverEndpoint, *conn.GetEndpoint())
+		connections = append(connections, conn)
+	}
+
+	statuses := sess.GetEndpointStatus()
+	assert.Equal(t, 2, len(statuses))
+
+	assert.False(t, statuses[0].Healthy)
+	assert.Equal(t, 1, statuses[0].Failures)
+	assert.Equal(t, 0, statuses[0].Connections)
+
+	assert.True(t, statuses[1].Healthy)
+	assert.Equal(t, 3, statuses[1].Connections)
+
+	for _, conn := range connections {
+		err = sess.ReturnConnection(conn)
+		failError(t, err)
+	}
+}
@@ -0,0 +1,257 @@
+package testcases
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSLConfig(t *testing.T) {
+	t.Run("test TLSVersionAndCipherSuite", testTLSVersionAndCipherSuite)
+	t.Run("test ClientCertificate", testClientCertificate)
+	t.Run("test PublicKeyPinning", testPublicKeyPinning)
+}
+
+// createTestCertificate creates a self-signed certificate for localhost
+func createTestCertificate(t *testing.T) (tls.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	failError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	failError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	failError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	failError(t, err)
+
+	cert.Leaf, err = x509.ParseCertificate(certDER)
+	failError(t, err)
+
+	return cert, certPEM, keyPEM
+}
+
+// handshake runs TLS handshake over loopback, returns the client error
+// the client reads a byte after handshake, as servers reject client certificates after handshake in TLS 1.3
+func handshake(serverConfig *tls.Config, clientConfig *tls.Config) error {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	go func() {
+		serverSocket, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer serverSocket.Close()
+
+		serverSocket.Write([]byte{0})
+	}()
+
+	clientSocket, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return err
+	}
+	defer clientSocket.Close()
+
+	clientSocket.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	buf := make([]byte, 1)
+	_, err = clientSocket.Read(buf)
+	return err
+}
+
+func testTLSVersionAndCipherSuite(t *testing.T) {
+	version, err := types.GetTLSVersion("1.2")
+	failError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), version)
+
+	version, err = types.GetTLSVersion("TLS1.3")
+	failError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+
+	_, err = types.GetTLSVersion("2.0")
+	assert.Error(t, err)
+
+	suite, err := types.GetCipherSuite("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	failError(t, err)
+	assert.Equal(t, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, suite)
+
+	_, err = types.GetCipherSuite("UNKNOWN_SUITE")
+	assert.Error(t, err)
+
+	sslConfig := &types.IRODSSSLConfig{
+		MinTLSVersion: tls.VersionTLS13,
+		TLSConfig: &tls.Config{
+			ServerName: "base.example.com",
+		},
+	}
+
+	tlsConfig, err := sslConfig.GetTLSConfig("localhost", false)
+	failError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.Equal(t, "base.example.com", tlsConfig.ServerName)
+	// base config is not modified
+	assert.Equal(t, uint16(0), sslConfig.TLSConfig.MinVersion)
+}
+
+func testClientCertificate(t *testing.T) {
+	serverCert, serverCertPEM, _ := createTestCertificate(t)
+	clientCert, clientCertPEM, clientKeyPEM := createTestCertificate(t)
+
+	tempDir := t.TempDir()
+	caFile := filepath.Join(tempDir, "ca.pem")
+	certFile := filepath.Join(tempDir, "client.pem")
+	keyFile := filepath.Join(tempDir, "client.key")
+
+	failError(t, os.WriteFile(caFile, serverCertPEM, 0600))
+	failError(t, os.WriteFile(certFile, clientCertPEM, 0600))
+	failError(t, os.WriteFile(keyFile, clientKeyPEM, 0600))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+
+	// with client certificate files
+	sslConfig := &types.IRODSSSLConfig{
+		CACertificateFile:    caFile,
+		CertificateChainFile: certFile,
+		CertificateKeyFile:   keyFile,
+	}
+
+	tlsConfig, err := sslConfig.GetTLSConfig("localhost", false)
+	failError(t, err)
+
+	err = handshake(serverConfig, tlsConfig)
+	failError(t, err)
+
+	// with callback
+	called := false
+	sslConfig = &types.IRODSSSLConfig{
+		CACertificateFile: caFile,
+		GetClientCertificate: func(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			called = true
+			return &clientCert, nil
+		},
+	}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("localhost", false)
+	failError(t, err)
+
+	err = handshake(serverConfig, tlsConfig)
+	failError(t, err)
+	assert.True(t, called)
+
+	// without client certificate
+	sslConfig = &types.IRODSSSLConfig{
+		CACertificateFile: caFile,
+	}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("localhost", false)
+	failError(t, err)
+
+	err = handshake(serverConfig, tlsConfig)
+	assert.Error(t, err)
+}
+
+func testPublicKeyPinning(t *testing.T) {
+	serverCert, _, _ := createTestCertificate(t)
+	otherCert, _, _ := createTestCertificate(t)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+	}
+
+	// pinned, verification of the self-signed certificate is skipped
+	sslConfig := &types.IRODSSSLConfig{
+		PinnedPublicKeys: []string{"sha256//" + types.GetPublicKeyPin(serverCert.Leaf)},
+	}
+
+	tlsConfig, err := sslConfig.GetTLSConfig("localhost", true)
+	failError(t, err)
+
+	err = handshake(serverConfig, tlsConfig)
+	failError(t, err)
+
+	// not pinned
+	sslConfig = &types.IRODSSSLConfig{
+		PinnedPublicKeys: []string{types.GetPublicKeyPin(otherCert.Leaf)},
+	}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("localhost", true)
+	failError(t, err)
+
+	err = handshake(serverConfig, tlsConfig)
+	assert.Error(t, err)
+	// the pinned certificate appended to the chain sent by the server is not trusted
+	appendedCert := serverCert
+	appendedCert.Certificate = append(append([][]byte{}, serverCert.Certificate...), otherCert.Certificate...)
+
+	appendedServerConfig := &tls.Config{
+		Certificates: []tls.Certificate{appendedCert},
+	}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("localhost", true)
+	failError(t, err)
+
+	err = handshake(appendedServerConfig, tlsConfig)
+	assert.Error(t, err)
+
+	// verified
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(serverCert.Leaf)
+
+	sslConfig = &types.IRODSSSLConfig{
+		TLSConfig: &tls.Config{
+			RootCAs: rootCAs,
+		},
+		PinnedPublicKeys: []string{types.GetPublicKeyPin(otherCert.Leaf)},
+	}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("localhost", false)
+	failError(t, err)
+
+	err = handshake(appendedServerConfig, tlsConfig)
+	assert.Error(t, err)
+
+	sslConfig.PinnedPublicKeys = []string{types.GetPublicKeyPin(serverCert.Leaf)}
+
+	tlsConfig, err = sslConfig.GetTLSConfig("localhost", false)
+	failError(t, err)
+
+	err = handshake(appendedServerConfig, tlsConfig)
+	failError(t, err)
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/cyverse/go-irodsclient/irods/connection"
 	"github.com/cyverse/go-irodsclient/irods/fs"
+	"github.com/cyverse/go-irodsclient/irods/types"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSystem(t *testing.T) {
@@ -13,6 +15,7 @@ func TestSystem(t *testing.T) {
 	defer shutdown()
 
 	t.Run("test ProcessStat", testProcessStat)
+	t.Run("test ServerInfo", testServerInfo)
 }
 
 func testProcessStat(t *testing.T) {
@@ -32,3 +35,24 @@ func testProcessStat(t *testing.T) {
 		t.Logf("process - %s\n", process.ToString())
 	}
 }
+
+func testServerInfo(t *testing.T) {
+	account := GetTestAccount()
+
+	account.ClientServerNegotiation = false
+
+	conn := connection.NewIRODSConnection(account, 300*time.Second, "go-irodsclient-test")
+	err := conn.Connect()
+	failError(t, err)
+	defer conn.Disconnect()
+
+	serverInfo, err := fs.GetServerInfo(conn)
+	failError(t, err)
+
+	t.Logf("server info - %s\n", serverInfo.ToString())
+
+	assert.Equal(t, types.IRODSServerTypeProvider, serverInfo.Type)
+	assert.Equal(t, account.ClientZone, serverInfo.Zone)
+	assert.Equal(t, conn.GetVersion().ReleaseVersion, serverInfo.ReleaseVersion)
+	assert.False(t, serverInfo.BootTime.IsZero())
+}